@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
@@ -12,9 +14,11 @@ import (
 
 	"github.com/sanskarchoudhry/pokedex-backend/internal/config"
 	"github.com/sanskarchoudhry/pokedex-backend/internal/database"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/mailer"
 	"github.com/sanskarchoudhry/pokedex-backend/internal/repository"
 	"github.com/sanskarchoudhry/pokedex-backend/internal/server"
 	"github.com/sanskarchoudhry/pokedex-backend/internal/service"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/utils"
 )
 
 func main() {
@@ -30,17 +34,76 @@ func main() {
 	// 3. Database
 	dbService := database.New(cfg.DBUrl)
 
-	// 4. Wiring
+	// `api migrate ...` manages the schema and exits without starting the server
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		code := runMigrate(context.Background(), dbService.GetDB(), os.Args[2:])
+		dbService.Close()
+		os.Exit(code)
+	}
+
+	if cfg.AutoMigrate {
+		migrator, err := newMigrator(dbService.GetDB())
+		if err != nil {
+			logger.Error("Failed to load migrations", "error", err)
+			os.Exit(1)
+		}
+		ran, err := migrator.Up(context.Background())
+		if err != nil {
+			logger.Error("Failed to apply migrations", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("Migrations applied", "count", len(ran))
+	}
+
+	// 4. JWT Signing Keys
+	jwtManager, err := newJWTManager(cfg.JWT)
+	if err != nil {
+		logger.Error("Failed to load JWT signing keys", "error", err)
+		os.Exit(1)
+	}
+
+	// 5. Mail
+	mail, err := newMailer(cfg.Mail)
+	if err != nil {
+		logger.Error("Failed to set up mailer", "error", err)
+		os.Exit(1)
+	}
+
+	// 6. Wiring
 	userRepo := repository.NewUserRepository(dbService.GetDB())
 	tokenRepo := repository.NewTokenRepository(dbService.GetDB())
+	mfaRepo := repository.NewMFARepository(dbService.GetDB())
 	pokeRepo := repository.NewPokemonRepository(dbService.GetDB())
+	speciesRepo := repository.NewSpeciesRepository(dbService.GetDB())
+	teamRepo := repository.NewTeamRepository(dbService.GetDB())
+	tradeRepo := repository.NewTradeRepository(dbService.GetDB())
+	boxRepo := repository.NewBoxRepository(dbService.GetDB())
+	eventRepo := repository.NewEventRepository(dbService.GetDB())
+	auditRepo := repository.NewAuditRepository(dbService.GetDB())
+	loginAttempts, err := newLoginAttemptStore(cfg.Login, dbService.GetDB())
+	if err != nil {
+		logger.Error("Failed to set up login throttling", "error", err)
+		os.Exit(1)
+	}
 
-	authSvc := service.NewAuthService(userRepo, tokenRepo)
-	pokeSvc := service.NewPokemonService(pokeRepo)
-
-	srv := server.NewServer(cfg, logger, authSvc, pokeSvc)
-
-	// 5. Start Server in a Goroutine (Background)
+	authSvc := service.NewAuthService(userRepo, tokenRepo, mfaRepo, jwtManager, mail, cfg.AppURL, loginAttempts, service.LoginPolicy{
+		FreeAttempts:    cfg.Login.FreeAttempts,
+		BaseDelay:       cfg.Login.BaseDelay,
+		MaxDelay:        cfg.Login.MaxDelay,
+		AccountLockout:  cfg.Login.AccountLockout,
+		IPLockout:       cfg.Login.IPLockout,
+		LockoutDuration: cfg.Login.LockoutDuration,
+	})
+	pokeSvc := service.NewPokemonService(pokeRepo, speciesRepo, eventRepo)
+	teamSvc := service.NewTeamService(teamRepo, speciesRepo)
+	speciesSvc := service.NewSpeciesService(speciesRepo)
+	tradeSvc := service.NewTradeService(tradeRepo, pokeRepo, speciesRepo)
+	boxSvc := service.NewBoxService(boxRepo, pokeRepo, speciesRepo)
+	adminSvc := service.NewAdminService(userRepo, tokenRepo, auditRepo, pokeSvc)
+
+	srv := server.NewServer(cfg, logger, jwtManager, authSvc, pokeSvc, teamSvc, speciesSvc, tradeSvc, boxSvc, adminSvc)
+
+	// 7. Start Server in a Goroutine (Background)
 	go func() {
 		logger.Info("Server starting", "port", cfg.Port)
 		if err := srv.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
@@ -49,7 +112,7 @@ func main() {
 		}
 	}()
 
-	// 6. Wait for Shutdown Signal
+	// 8. Wait for Shutdown Signal
 	// We create a channel that listens for OS signals (Ctrl+C, Docker Stop)
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
@@ -59,7 +122,7 @@ func main() {
 
 	logger.Info("Server shutting down...")
 
-	// 7. Graceful Shutdown
+	// 9. Graceful Shutdown
 	// Create a context with a 5-second timeout.
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -68,7 +131,7 @@ func main() {
 		logger.Error("Server forced to shutdown", "error", err)
 	}
 
-	// 8. Close Database Connections
+	// 10. Close Database Connections
 	// Now that no more requests are coming in, it is safe to close the DB.
 	if err := dbService.Close(); err != nil {
 		logger.Error("Error closing database", "error", err)
@@ -76,3 +139,55 @@ func main() {
 
 	logger.Info("Server exited properly")
 }
+
+// newJWTManager uses the configured key files, falling back to an HS256 shared
+// secret when none are set. There is no built-in secret: without either one it fails.
+func newJWTManager(cfg config.JWTConfig) (*utils.JWTManager, error) {
+	if len(cfg.KeyFiles) == 0 {
+		if cfg.Secret == "" {
+			return nil, errors.New("set JWT_KEY_FILES or JWT_SECRET")
+		}
+		return utils.NewJWTManager([]utils.SigningKey{utils.NewHMACKey("hs256", []byte(cfg.Secret))}, "")
+	}
+
+	keys, err := utils.LoadSigningKeys(cfg.KeyFiles)
+	if err != nil {
+		return nil, err
+	}
+	return utils.NewJWTManager(keys, cfg.ActiveKeyID)
+}
+
+// newMailer builds the configured driver. Like the JWT keys, it has to be chosen
+// explicitly: without MAIL_DRIVER it fails.
+func newMailer(cfg config.MailConfig) (mailer.Mailer, error) {
+	switch cfg.Driver {
+	case "smtp":
+		return mailer.NewSMTPMailer(mailer.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.From,
+		}), nil
+	case "file":
+		return mailer.NewFileMailer(cfg.File, cfg.From)
+	case "":
+		return nil, errors.New("set MAIL_DRIVER to smtp or file")
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q, expected smtp or file", cfg.Driver)
+	}
+}
+
+// newLoginAttemptStore remembers failures for the whole lockout, so a locked
+// account stays locked until it ends.
+func newLoginAttemptStore(cfg config.LoginConfig, db *sql.DB) (repository.LoginAttemptStore, error) {
+	window := max(cfg.LockoutDuration, cfg.MaxDelay)
+	switch cfg.Store {
+	case "postgres":
+		return repository.NewLoginAttemptStore(db, window), nil
+	case "memory":
+		return repository.NewMemoryLoginAttemptStore(window), nil
+	default:
+		return nil, fmt.Errorf("unknown login attempt store %q, expected postgres or memory", cfg.Store)
+	}
+}
@@ -0,0 +1,75 @@
+// Command importer loads species data from a local PokeAPI dump into the catalog.
+//
+//	go run ./cmd/importer -format csv -dir ./pokeapi/data/v2/csv
+//	go run ./cmd/importer -format json -dir ./api-data/data/api/v2
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/config"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/database"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/importer"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/repository"
+)
+
+func main() {
+	format := flag.String("format", "csv", "dump format: csv or json")
+	dir := flag.String("dir", "", "path to the dump (data/v2/csv or data/api/v2)")
+	flag.Parse()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	if *dir == "" {
+		logger.Error("-dir is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// 1. Parse the dump before touching the database
+	var (
+		catalog *importer.Catalog
+		err     error
+	)
+	switch *format {
+	case "csv":
+		catalog, err = importer.LoadCSV(*dir)
+	case "json":
+		catalog, err = importer.LoadJSON(*dir)
+	default:
+		logger.Error("Unknown format", "format", *format)
+		os.Exit(2)
+	}
+	if err != nil {
+		logger.Error("Failed to read dump", "dir", *dir, "error", err)
+		os.Exit(1)
+	}
+	if err := catalog.Validate(); err != nil {
+		logger.Error("Dump failed validation", "error", err)
+		os.Exit(1)
+	}
+
+	// 2. Store it
+	cfg := config.LoadConfig()
+	dbService := database.New(cfg.DBUrl)
+	defer dbService.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	speciesRepo := repository.NewSpeciesRepository(dbService.GetDB())
+	if err := speciesRepo.UpsertGenerations(ctx, catalog.Generations); err != nil {
+		logger.Error("Failed to import generations", "error", err)
+		os.Exit(1)
+	}
+	if err := speciesRepo.UpsertSpecies(ctx, catalog.Species); err != nil {
+		logger.Error("Failed to import species", "error", err)
+		os.Exit(1)
+	}
+
+	logger.Info("Import finished", "generations", len(catalog.Generations), "species", len(catalog.Species))
+}
@@ -0,0 +1,9 @@
+// Package db embeds the SQL migrations so the binary can migrate without the source tree.
+package db
+
+import "embed"
+
+// Migrations holds every file in migrations/, named <version>_<name>.<up|down>.sql.
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS pokemons;
\ No newline at end of file
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS pokemons (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    pokedex_id INTEGER NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    nickname VARCHAR(100) NOT NULL DEFAULT '',
+    type VARCHAR(50) NOT NULL,
+    height INTEGER NOT NULL,
+    weight INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_pokemons_user_id ON pokemons(user_id);
@@ -0,0 +1,5 @@
+-- Existing rows get their email as username so the NOT NULL/UNIQUE constraints hold.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS username VARCHAR(255);
+UPDATE users SET username = email WHERE username IS NULL;
+ALTER TABLE users ALTER COLUMN username SET NOT NULL;
+ALTER TABLE users ADD CONSTRAINT users_username_key UNIQUE (username);
@@ -0,0 +1,2 @@
+-- Registration only collects an email, so username was never filled in.
+ALTER TABLE users DROP COLUMN IF EXISTS username;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_pokemons_user_created_at;
+DROP INDEX IF EXISTS idx_pokemons_user_height;
+DROP INDEX IF EXISTS idx_pokemons_user_weight;
+DROP INDEX IF EXISTS idx_pokemons_user_pokedex_id;
+DROP INDEX IF EXISTS idx_pokemons_user_name;
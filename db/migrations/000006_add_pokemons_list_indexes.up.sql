@@ -0,0 +1,6 @@
+-- Keyset pagination walks (user_id, <sort column>, id); one index per sortable column.
+CREATE INDEX IF NOT EXISTS idx_pokemons_user_name ON pokemons(user_id, name, id);
+CREATE INDEX IF NOT EXISTS idx_pokemons_user_pokedex_id ON pokemons(user_id, pokedex_id, id);
+CREATE INDEX IF NOT EXISTS idx_pokemons_user_weight ON pokemons(user_id, weight, id);
+CREATE INDEX IF NOT EXISTS idx_pokemons_user_height ON pokemons(user_id, height, id);
+CREATE INDEX IF NOT EXISTS idx_pokemons_user_created_at ON pokemons(user_id, created_at, id);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS species;
\ No newline at end of file
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS species (
+    id INTEGER PRIMARY KEY, -- National Pokedex number
+    name VARCHAR(100) NOT NULL UNIQUE,
+    primary_type VARCHAR(20) NOT NULL,
+    secondary_type VARCHAR(20),
+    height INTEGER NOT NULL,
+    weight INTEGER NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
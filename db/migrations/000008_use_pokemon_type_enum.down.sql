@@ -0,0 +1,11 @@
+ALTER TABLE pokemons ADD COLUMN type VARCHAR(50);
+UPDATE pokemons
+SET type = primary_type::text || COALESCE('/' || secondary_type::text, '');
+ALTER TABLE pokemons ALTER COLUMN type SET NOT NULL;
+ALTER TABLE pokemons DROP COLUMN primary_type, DROP COLUMN secondary_type;
+
+ALTER TABLE species
+    ALTER COLUMN primary_type TYPE VARCHAR(20) USING primary_type::text,
+    ALTER COLUMN secondary_type TYPE VARCHAR(20) USING secondary_type::text;
+
+DROP TYPE IF EXISTS pokemon_type;
@@ -0,0 +1,56 @@
+CREATE TYPE pokemon_type AS ENUM (
+    'normal', 'fire', 'water', 'electric', 'grass', 'ice',
+    'fighting', 'poison', 'ground', 'flying', 'psychic', 'bug',
+    'rock', 'ghost', 'dragon', 'dark', 'steel', 'fairy'
+);
+
+-- The catalog is written by the importer, so its values are already canonical.
+ALTER TABLE species
+    ALTER COLUMN primary_type TYPE pokemon_type USING LOWER(TRIM(primary_type))::pokemon_type,
+    ALTER COLUMN secondary_type TYPE pokemon_type USING NULLIF(LOWER(TRIM(secondary_type)), '')::pokemon_type;
+
+ALTER TABLE pokemons
+    ADD COLUMN primary_type pokemon_type,
+    ADD COLUMN secondary_type pokemon_type;
+
+-- 1. Rows whose species is in the catalog take the canonical typing.
+UPDATE pokemons p
+SET primary_type = s.primary_type,
+    secondary_type = s.secondary_type
+FROM species s
+WHERE s.id = p.pokedex_id;
+
+-- 2. Everything else is parsed from the old free-form column: "Fire", "grass/poison", "Grass, Poison".
+WITH parsed AS (
+    SELECT id,
+           LOWER(split_part(regexp_replace(TRIM(type), '\s*[/,]\s*|\s+', '/', 'g'), '/', 1)) AS first,
+           LOWER(split_part(regexp_replace(TRIM(type), '\s*[/,]\s*|\s+', '/', 'g'), '/', 2)) AS second
+    FROM pokemons
+    WHERE primary_type IS NULL
+), known AS (
+    SELECT unnest(enum_range(NULL::pokemon_type))::text AS name
+)
+UPDATE pokemons p
+SET primary_type = parsed.first::pokemon_type,
+    secondary_type = CASE
+        WHEN parsed.second <> parsed.first AND parsed.second IN (SELECT name FROM known)
+        THEN parsed.second::pokemon_type
+    END
+FROM parsed
+WHERE p.id = parsed.id
+  AND parsed.first IN (SELECT name FROM known);
+
+-- 3. Refuse to guess for typos like "fyre"; the operator has to fix those rows first.
+DO $$
+DECLARE
+    unresolved INTEGER;
+BEGIN
+    SELECT COUNT(*) INTO unresolved FROM pokemons WHERE primary_type IS NULL;
+    IF unresolved > 0 THEN
+        RAISE EXCEPTION '% pokemons have a type that matches neither the species catalog nor a known type', unresolved
+            USING HINT = 'SELECT id, type FROM pokemons WHERE primary_type IS NULL; fix them, then re-run the migration';
+    END IF;
+END $$;
+
+ALTER TABLE pokemons ALTER COLUMN primary_type SET NOT NULL;
+ALTER TABLE pokemons DROP COLUMN type;
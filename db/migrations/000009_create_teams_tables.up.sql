@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS teams (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_teams_user_id ON teams(user_id);
+
+-- A party holds up to six Pokemon, each in its own slot and at most once.
+CREATE TABLE IF NOT EXISTS team_members (
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    pokemon_id INTEGER NOT NULL REFERENCES pokemons(id) ON DELETE CASCADE,
+    slot SMALLINT NOT NULL CHECK (slot BETWEEN 1 AND 6),
+    PRIMARY KEY (team_id, slot),
+    UNIQUE (team_id, pokemon_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_members_pokemon_id ON team_members(pokemon_id);
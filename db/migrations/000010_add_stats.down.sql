@@ -0,0 +1,26 @@
+ALTER TABLE pokemons
+    DROP CONSTRAINT IF EXISTS pokemons_ev_total_check,
+    DROP COLUMN level,
+    DROP COLUMN nature,
+    DROP COLUMN ability,
+    DROP COLUMN iv_hp,
+    DROP COLUMN iv_attack,
+    DROP COLUMN iv_defense,
+    DROP COLUMN iv_sp_attack,
+    DROP COLUMN iv_sp_defense,
+    DROP COLUMN iv_speed,
+    DROP COLUMN ev_hp,
+    DROP COLUMN ev_attack,
+    DROP COLUMN ev_defense,
+    DROP COLUMN ev_sp_attack,
+    DROP COLUMN ev_sp_defense,
+    DROP COLUMN ev_speed;
+
+ALTER TABLE species
+    DROP COLUMN base_hp,
+    DROP COLUMN base_attack,
+    DROP COLUMN base_defense,
+    DROP COLUMN base_sp_attack,
+    DROP COLUMN base_sp_defense,
+    DROP COLUMN base_speed,
+    DROP COLUMN abilities;
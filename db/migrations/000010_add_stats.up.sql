@@ -0,0 +1,28 @@
+ALTER TABLE species
+    ADD COLUMN base_hp SMALLINT NOT NULL DEFAULT 0,
+    ADD COLUMN base_attack SMALLINT NOT NULL DEFAULT 0,
+    ADD COLUMN base_defense SMALLINT NOT NULL DEFAULT 0,
+    ADD COLUMN base_sp_attack SMALLINT NOT NULL DEFAULT 0,
+    ADD COLUMN base_sp_defense SMALLINT NOT NULL DEFAULT 0,
+    ADD COLUMN base_speed SMALLINT NOT NULL DEFAULT 0,
+    ADD COLUMN abilities TEXT[] NOT NULL DEFAULT '{}'; -- Slot order, hidden ability last
+
+-- Existing Pokemon become neutral level 5s with no training; ability is filled in on next edit.
+ALTER TABLE pokemons
+    ADD COLUMN level SMALLINT NOT NULL DEFAULT 5 CHECK (level BETWEEN 1 AND 100),
+    ADD COLUMN nature VARCHAR(20) NOT NULL DEFAULT 'hardy',
+    ADD COLUMN ability VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN iv_hp SMALLINT NOT NULL DEFAULT 0 CHECK (iv_hp BETWEEN 0 AND 31),
+    ADD COLUMN iv_attack SMALLINT NOT NULL DEFAULT 0 CHECK (iv_attack BETWEEN 0 AND 31),
+    ADD COLUMN iv_defense SMALLINT NOT NULL DEFAULT 0 CHECK (iv_defense BETWEEN 0 AND 31),
+    ADD COLUMN iv_sp_attack SMALLINT NOT NULL DEFAULT 0 CHECK (iv_sp_attack BETWEEN 0 AND 31),
+    ADD COLUMN iv_sp_defense SMALLINT NOT NULL DEFAULT 0 CHECK (iv_sp_defense BETWEEN 0 AND 31),
+    ADD COLUMN iv_speed SMALLINT NOT NULL DEFAULT 0 CHECK (iv_speed BETWEEN 0 AND 31),
+    ADD COLUMN ev_hp SMALLINT NOT NULL DEFAULT 0 CHECK (ev_hp BETWEEN 0 AND 252),
+    ADD COLUMN ev_attack SMALLINT NOT NULL DEFAULT 0 CHECK (ev_attack BETWEEN 0 AND 252),
+    ADD COLUMN ev_defense SMALLINT NOT NULL DEFAULT 0 CHECK (ev_defense BETWEEN 0 AND 252),
+    ADD COLUMN ev_sp_attack SMALLINT NOT NULL DEFAULT 0 CHECK (ev_sp_attack BETWEEN 0 AND 252),
+    ADD COLUMN ev_sp_defense SMALLINT NOT NULL DEFAULT 0 CHECK (ev_sp_defense BETWEEN 0 AND 252),
+    ADD COLUMN ev_speed SMALLINT NOT NULL DEFAULT 0 CHECK (ev_speed BETWEEN 0 AND 252),
+    ADD CONSTRAINT pokemons_ev_total_check
+        CHECK (ev_hp + ev_attack + ev_defense + ev_sp_attack + ev_sp_defense + ev_speed <= 510);
@@ -0,0 +1,3 @@
+ALTER TABLE pokemons
+    DROP COLUMN held_item,
+    DROP COLUMN moves;
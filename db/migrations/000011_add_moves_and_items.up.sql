@@ -0,0 +1,3 @@
+ALTER TABLE pokemons
+    ADD COLUMN held_item VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN moves TEXT[] NOT NULL DEFAULT '{}' CHECK (cardinality(moves) <= 4);
@@ -0,0 +1,7 @@
+ALTER TABLE pokemons DROP COLUMN friendship;
+
+DROP TABLE IF EXISTS species_evolutions;
+
+ALTER TABLE species
+    DROP COLUMN evolves_from_id,
+    DROP COLUMN chain_id;
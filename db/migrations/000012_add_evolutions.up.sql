@@ -0,0 +1,26 @@
+-- Deferred so a whole catalog can be imported in one transaction in any order
+ALTER TABLE species
+    ADD COLUMN evolves_from_id INTEGER REFERENCES species(id) DEFERRABLE INITIALLY DEFERRED,
+    ADD COLUMN chain_id INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_species_chain_id ON species(chain_id);
+
+-- One row per way of evolving; a pair of species can have several
+CREATE TABLE IF NOT EXISTS species_evolutions (
+    id SERIAL PRIMARY KEY,
+    from_species_id INTEGER NOT NULL REFERENCES species(id) ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED,
+    to_species_id INTEGER NOT NULL REFERENCES species(id) ON DELETE CASCADE DEFERRABLE INITIALLY DEFERRED,
+    trigger VARCHAR(30) NOT NULL, -- level-up, use-item, trade, ...
+    min_level SMALLINT NOT NULL DEFAULT 0,
+    min_friendship SMALLINT NOT NULL DEFAULT 0,
+    item VARCHAR(50) NOT NULL DEFAULT '', -- Item used on the Pokemon
+    held_item VARCHAR(50) NOT NULL DEFAULT '',
+    trade_species_id INTEGER, -- Must be traded for this species
+    time_of_day VARCHAR(10) NOT NULL DEFAULT '',
+    other_conditions BOOLEAN NOT NULL DEFAULT FALSE -- Location, known move, gender, ...
+);
+
+CREATE INDEX IF NOT EXISTS idx_species_evolutions_from ON species_evolutions(from_species_id);
+
+ALTER TABLE pokemons
+    ADD COLUMN friendship SMALLINT NOT NULL DEFAULT 70 CHECK (friendship BETWEEN 0 AND 255);
@@ -0,0 +1,31 @@
+-- Pokemon ids carry no foreign key so a trade's record outlives a released Pokemon.
+CREATE TABLE IF NOT EXISTS trades (
+    id SERIAL PRIMARY KEY,
+    proposer_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    recipient_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    offered_pokemon_id INTEGER NOT NULL,
+    requested_pokemon_id INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'accepted', 'declined', 'cancelled')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    CHECK (proposer_id <> recipient_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_trades_proposer_id ON trades(proposer_id);
+CREATE INDEX IF NOT EXISTS idx_trades_recipient_id ON trades(recipient_id);
+
+-- One row per Pokemon that changed hands, including any evolution on arrival
+CREATE TABLE IF NOT EXISTS trade_transfers (
+    id SERIAL PRIMARY KEY,
+    trade_id INTEGER NOT NULL REFERENCES trades(id) ON DELETE CASCADE,
+    pokemon_id INTEGER NOT NULL,
+    from_user_id INTEGER NOT NULL,
+    to_user_id INTEGER NOT NULL,
+    pokedex_id_before INTEGER NOT NULL,
+    pokedex_id_after INTEGER NOT NULL,
+    transferred_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_trade_transfers_trade_id ON trade_transfers(trade_id);
+CREATE INDEX IF NOT EXISTS idx_trade_transfers_pokemon_id ON trade_transfers(pokemon_id);
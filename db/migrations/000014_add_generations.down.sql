@@ -0,0 +1,3 @@
+ALTER TABLE species DROP COLUMN generation_id;
+
+DROP TABLE IF EXISTS generations;
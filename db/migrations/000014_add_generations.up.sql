@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS generations (
+    id INTEGER PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE, -- PokeAPI identifier, e.g. generation-i
+    region VARCHAR(50) NOT NULL DEFAULT '' -- Main region, e.g. kanto
+);
+
+-- Species imported before generations existed keep a NULL until the next import
+ALTER TABLE species
+    ADD COLUMN generation_id INTEGER REFERENCES generations(id);
+
+CREATE INDEX IF NOT EXISTS idx_species_generation_id ON species(generation_id);
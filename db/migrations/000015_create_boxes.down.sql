@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_pokemons_tags;
+
+ALTER TABLE pokemons
+    DROP CONSTRAINT pokemons_box_slot_unique,
+    DROP CONSTRAINT pokemons_box_placement,
+    DROP COLUMN tags,
+    DROP COLUMN box_slot,
+    DROP COLUMN box_id;
+
+DROP TABLE IF EXISTS boxes;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS boxes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_boxes_user_id ON boxes(user_id);
+
+-- A Pokemon is either in a box slot or unboxed. The unique slot is deferrable
+-- so a swap can pass through a state where two Pokemon share a slot.
+ALTER TABLE pokemons
+    ADD COLUMN box_id INTEGER REFERENCES boxes(id),
+    ADD COLUMN box_slot SMALLINT CHECK (box_slot BETWEEN 1 AND 30),
+    ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}',
+    ADD CONSTRAINT pokemons_box_placement CHECK ((box_id IS NULL) = (box_slot IS NULL)),
+    ADD CONSTRAINT pokemons_box_slot_unique UNIQUE (box_id, box_slot) DEFERRABLE INITIALLY IMMEDIATE;
+
+CREATE INDEX IF NOT EXISTS idx_pokemons_tags ON pokemons USING GIN (tags);
@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS idx_pokemons_user_shiny;
+
+ALTER TABLE pokemons
+    DROP COLUMN shiny,
+    DROP COLUMN gender,
+    DROP COLUMN form,
+    DROP COLUMN ball,
+    DROP COLUMN original_trainer,
+    DROP COLUMN caught_at,
+    DROP COLUMN caught_location;
+
+ALTER TABLE species
+    DROP COLUMN gender_rate,
+    DROP COLUMN forms;
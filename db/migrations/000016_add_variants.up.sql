@@ -0,0 +1,24 @@
+-- gender_rate is in eighths female, -1 for genderless species; NULL until the next import.
+-- forms lists the alternate forms a species can be caught in, e.g. {alola}.
+ALTER TABLE species
+    ADD COLUMN gender_rate SMALLINT CHECK (gender_rate BETWEEN -1 AND 8),
+    ADD COLUMN forms TEXT[] NOT NULL DEFAULT '{}';
+
+-- An empty gender marks Pokemon recorded before genders were tracked
+ALTER TABLE pokemons
+    ADD COLUMN shiny BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN gender VARCHAR(10) NOT NULL DEFAULT '' CHECK (gender IN ('', 'male', 'female', 'genderless')),
+    ADD COLUMN form VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN ball VARCHAR(50) NOT NULL DEFAULT 'poke-ball',
+    ADD COLUMN original_trainer VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN caught_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN caught_location VARCHAR(100) NOT NULL DEFAULT '';
+
+-- Existing Pokemon were caught when they were recorded
+UPDATE pokemons SET caught_at = COALESCE(created_at, CURRENT_TIMESTAMP);
+ALTER TABLE pokemons
+    ALTER COLUMN caught_at SET NOT NULL,
+    ALTER COLUMN caught_at SET DEFAULT CURRENT_TIMESTAMP;
+
+-- Shinies are rare, so a partial index keeps "show my shinies" cheap
+CREATE INDEX IF NOT EXISTS idx_pokemons_user_shiny ON pokemons(user_id) WHERE shiny;
@@ -0,0 +1,29 @@
+-- Append-only history of each Pokemon. The Pokemon id carries no foreign key,
+-- so the history outlives a release; user_id is the owner when it happened and
+-- related_user_id the other party, e.g. the previous owner of a traded Pokemon.
+CREATE TABLE IF NOT EXISTS pokemon_events (
+    id SERIAL PRIMARY KEY,
+    pokemon_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    related_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    event_type VARCHAR(20) NOT NULL
+        CHECK (event_type IN ('caught', 'renamed', 'evolved', 'traded', 'released')),
+    pokedex_id INTEGER NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    nickname VARCHAR(100) NOT NULL DEFAULT '',
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_pokemon_events_pokemon_id ON pokemon_events(pokemon_id, id);
+CREATE INDEX IF NOT EXISTS idx_pokemon_events_user_id ON pokemon_events(user_id, id);
+CREATE INDEX IF NOT EXISTS idx_pokemon_events_related_user_id ON pokemon_events(related_user_id, id)
+    WHERE related_user_id IS NOT NULL;
+
+-- Pokemon caught before the log existed start with a caught event
+INSERT INTO pokemon_events (pokemon_id, user_id, event_type, pokedex_id, name, nickname, details, created_at)
+SELECT id, user_id, 'caught', pokedex_id, name, nickname,
+    jsonb_strip_nulls(jsonb_build_object('ball', ball, 'caught_location', NULLIF(caught_location, ''))),
+    created_at
+FROM pokemons
+ORDER BY id;
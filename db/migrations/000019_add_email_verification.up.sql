@@ -0,0 +1,6 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS verified_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS verification_sent_at TIMESTAMP WITH TIME ZONE;
+
+-- Accounts created before verification existed keep working
+UPDATE users SET verified_at = created_at WHERE verified_at IS NULL;
@@ -0,0 +1,9 @@
+-- Failed logins per account and per client IP, shared by every API instance.
+-- Rows are forgotten once their last failure is older than the lockout window.
+CREATE TABLE IF NOT EXISTS login_attempts (
+    key VARCHAR(320) PRIMARY KEY,
+    failures INTEGER NOT NULL,
+    last_failure_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_attempts_last_failure_at ON login_attempts(last_failure_at);
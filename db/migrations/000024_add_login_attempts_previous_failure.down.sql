@@ -0,0 +1,2 @@
+ALTER TABLE login_attempts
+    DROP COLUMN IF EXISTS previous_failure_at;
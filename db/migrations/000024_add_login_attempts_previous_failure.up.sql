@@ -0,0 +1,4 @@
+-- Attempts are charged before they are checked, so the backoff is measured
+-- from the failure before the latest one
+ALTER TABLE login_attempts
+    ADD COLUMN IF NOT EXISTS previous_failure_at TIMESTAMP WITH TIME ZONE;
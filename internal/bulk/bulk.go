@@ -0,0 +1,81 @@
+// Package bulk streams a user's Pokemon collection to and from CSV and NDJSON,
+// one row at a time, so neither side has to hold the whole collection in memory.
+//
+// Both formats carry the same fields. Rows only need a pokedex_id; name and types
+// are written on export for readability but ignored on import.
+package bulk
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+)
+
+// Format is a supported file format.
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+)
+
+// ParseFormat rejects anything but csv and ndjson.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(s); f {
+	case FormatCSV, FormatNDJSON:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unsupported format %q, expected csv or ndjson", s)
+	}
+}
+
+// ContentType is the MIME type to serve the format with.
+func (f Format) ContentType() string {
+	if f == FormatCSV {
+		return "text/csv"
+	}
+	return "application/x-ndjson"
+}
+
+// RowError is a row that couldn't be decoded. Reading can continue after it.
+type RowError struct {
+	Row int
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Reader yields one Pokemon per row and io.EOF after the last one.
+// A *RowError only affects its own row; any other error is fatal.
+type Reader interface {
+	Read() (*models.Pokemon, error)
+}
+
+// Writer encodes Pokemon one at a time. Flush must be called at the end.
+type Writer interface {
+	Write(p *models.Pokemon) error
+	Flush() error
+}
+
+// NewReader picks the decoder for the format.
+func NewReader(f Format, r io.Reader) (Reader, error) {
+	if f == FormatCSV {
+		return NewCSVReader(r)
+	}
+	return NewNDJSONReader(r), nil
+}
+
+// NewWriter picks the encoder for the format.
+func NewWriter(f Format, w io.Writer) (Writer, error) {
+	if f == FormatCSV {
+		return NewCSVWriter(w)
+	}
+	return NewNDJSONWriter(w), nil
+}
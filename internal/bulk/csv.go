@@ -0,0 +1,228 @@
+package bulk
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+)
+
+// listSeparator joins the moves or tags in a single CSV field
+const listSeparator = "|"
+
+// csvColumns is the export header. Import matches columns by name, so order
+// doesn't matter and unknown columns are ignored.
+var csvColumns = []string{
+	"id", "pokedex_id", "name", "nickname", "primary_type", "secondary_type",
+	"level", "nature", "ability", "held_item", "moves", "friendship", "tags",
+	"shiny", "gender", "form", "ball", "original_trainer", "caught_at", "caught_location",
+	"iv_hp", "iv_atk", "iv_def", "iv_spa", "iv_spd", "iv_spe",
+	"ev_hp", "ev_atk", "ev_def", "ev_spa", "ev_spd", "ev_spe",
+	"created_at",
+}
+
+// formulaPrefixes start a formula when a spreadsheet opens the file
+const formulaPrefixes = "=+-@"
+
+// statColumns are the suffixes of the iv_ and ev_ columns in StatSpread order
+var statColumns = [6]string{"hp", "atk", "def", "spa", "spd", "spe"}
+
+type csvReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+	row     int
+}
+
+// NewCSVReader reads the header row right away; it must contain pokedex_id.
+func NewCSVReader(r io.Reader) (Reader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // Short rows are reported per row instead
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["pokedex_id"]; !ok {
+		return nil, errors.New("header has no pokedex_id column")
+	}
+	return &csvReader{reader: reader, columns: columns}, nil
+}
+
+func (r *csvReader) Read() (*models.Pokemon, error) {
+	record, err := r.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+	r.row++
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return nil, &RowError{Row: r.row, Err: err}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := r.decode(record)
+	if err != nil {
+		return nil, &RowError{Row: r.row, Err: err}
+	}
+	return p, nil
+}
+
+func (r *csvReader) decode(record []string) (*models.Pokemon, error) {
+	field := func(name string) string {
+		i, ok := r.columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+	// Empty numbers mean "use the default"
+	number := func(name string) (int, error) {
+		value := field(name)
+		if value == "" {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, fmt.Errorf("column %s: %q is not a number", name, value)
+		}
+		return n, nil
+	}
+
+	p := &models.Pokemon{
+		Nickname: unescapeCell(field("nickname")),
+		Nature:   field("nature"),
+		Ability:  field("ability"),
+		HeldItem: field("held_item"),
+		Moves:    splitList(field("moves")),
+		Tags:     splitList(field("tags")),
+
+		Gender:          models.Gender(field("gender")),
+		Form:            field("form"),
+		Ball:            field("ball"),
+		OriginalTrainer: unescapeCell(field("original_trainer")),
+		CaughtLocation:  unescapeCell(field("caught_location")),
+	}
+	if shiny := field("shiny"); shiny != "" {
+		value, err := strconv.ParseBool(shiny)
+		if err != nil {
+			return nil, fmt.Errorf("column shiny: %q is not true or false", shiny)
+		}
+		p.Shiny = value
+	}
+	if caughtAt := field("caught_at"); caughtAt != "" {
+		value, err := time.Parse(time.RFC3339, caughtAt)
+		if err != nil {
+			return nil, fmt.Errorf("column caught_at: %q is not an RFC 3339 time", caughtAt)
+		}
+		p.CaughtAt = value
+	}
+
+	var err error
+	if p.PokedexID, err = number("pokedex_id"); err != nil {
+		return nil, err
+	}
+	if p.PokedexID == 0 {
+		return nil, errors.New("pokedex_id is required")
+	}
+	if p.Level, err = number("level"); err != nil {
+		return nil, err
+	}
+	if p.Friendship, err = number("friendship"); err != nil {
+		return nil, err
+	}
+
+	ivs := statFields(&p.IVs)
+	evs := statFields(&p.EVs)
+	for i, stat := range statColumns {
+		if *ivs[i], err = number("iv_" + stat); err != nil {
+			return nil, err
+		}
+		if *evs[i], err = number("ev_" + stat); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// splitList reads a "|"-separated field, e.g. "tackle|growl"
+func splitList(value string) []string {
+	items := []string{}
+	if value == "" {
+		return items
+	}
+	for _, item := range strings.Split(value, listSeparator) {
+		items = append(items, strings.TrimSpace(item))
+	}
+	return items
+}
+
+// escapeCell keeps spreadsheets from running free text as a formula by prefixing
+// it with a quote. Text already starting with a quote gets one too, so
+// unescapeCell can tell the two apart.
+func escapeCell(value string) string {
+	if value != "" && strings.ContainsRune(formulaPrefixes+"'", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// unescapeCell undoes escapeCell. A lone leading quote written by hand is kept.
+func unescapeCell(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(formulaPrefixes+"'", rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+func statFields(s *models.StatSpread) [6]*int {
+	return [6]*int{&s.HP, &s.Attack, &s.Defense, &s.SpAttack, &s.SpDefense, &s.Speed}
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+// NewCSVWriter writes the header row right away.
+func NewCSVWriter(w io.Writer) (Writer, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvColumns); err != nil {
+		return nil, err
+	}
+	return &csvWriter{writer: writer}, nil
+}
+
+func (w *csvWriter) Write(p *models.Pokemon) error {
+	record := []string{
+		strconv.Itoa(p.ID), strconv.Itoa(p.PokedexID), p.Name, escapeCell(p.Nickname),
+		string(p.Types.Primary), string(p.Types.Secondary),
+		strconv.Itoa(p.Level), p.Nature, p.Ability, p.HeldItem, strings.Join(p.Moves, listSeparator),
+		strconv.Itoa(p.Friendship), strings.Join(p.Tags, listSeparator),
+		strconv.FormatBool(p.Shiny), string(p.Gender), p.Form, p.Ball, escapeCell(p.OriginalTrainer),
+		p.CaughtAt.Format(time.RFC3339), escapeCell(p.CaughtLocation),
+	}
+	for _, v := range p.IVs.Values() {
+		record = append(record, strconv.Itoa(v))
+	}
+	for _, v := range p.EVs.Values() {
+		record = append(record, strconv.Itoa(v))
+	}
+	record = append(record, p.CreatedAt.Format(time.RFC3339))
+	return w.writer.Write(record)
+}
+
+func (w *csvWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
@@ -0,0 +1,69 @@
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+)
+
+// maxLineSize bounds a single NDJSON line
+const maxLineSize = 1 << 20
+
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	row     int
+}
+
+// NewNDJSONReader reads one JSON object per line, in the same shape the API returns Pokemon.
+// Blank lines are skipped.
+func NewNDJSONReader(r io.Reader) Reader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	return &ndjsonReader{scanner: scanner}
+}
+
+func (r *ndjsonReader) Read() (*models.Pokemon, error) {
+	for r.scanner.Scan() {
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		r.row++
+
+		var p models.Pokemon
+		if err := json.Unmarshal(line, &p); err != nil {
+			return nil, &RowError{Row: r.row, Err: err}
+		}
+		if p.PokedexID == 0 {
+			return nil, &RowError{Row: r.row, Err: errors.New("pokedex_id is required")}
+		}
+		return &p, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+type ndjsonWriter struct {
+	writer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func NewNDJSONWriter(w io.Writer) Writer {
+	writer := bufio.NewWriter(w)
+	return &ndjsonWriter{writer: writer, encoder: json.NewEncoder(writer)}
+}
+
+// Write emits the Pokemon followed by a newline
+func (w *ndjsonWriter) Write(p *models.Pokemon) error {
+	return w.encoder.Encode(p)
+}
+
+func (w *ndjsonWriter) Flush() error {
+	return w.writer.Flush()
+}
@@ -75,7 +75,7 @@ func (s *service) Health() map[string]string {
 	if err != nil {
 		stats["status"] = "down"
 		stats["error"] = fmt.Sprintf("db down: %v", err)
-		log.Fatalf(fmt.Sprintf("db down: %v", err)) // Log fatal error
+		log.Fatalf("db down: %v", err) // Log fatal error
 		return stats
 	}
 
@@ -0,0 +1,449 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+)
+
+// csvRecord is one row keyed by column name
+type csvRecord map[string]string
+
+func (r csvRecord) int(column string) (int, error) {
+	value, err := strconv.Atoi(r[column])
+	if err != nil {
+		return 0, fmt.Errorf("column %s: %w", column, err)
+	}
+	return value, nil
+}
+
+// optionalInt reads a nullable integer column, treating an empty value as 0
+func (r csvRecord) optionalInt(column string) (int, error) {
+	if r[column] == "" {
+		return 0, nil
+	}
+	return r.int(column)
+}
+
+// readCSV loads a PokeAPI CSV file (data/v2/csv/<name>) into memory
+func readCSV(dir, name string) ([]csvRecord, error) {
+	f, err := os.Open(filepath.Join(dir, name))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%s: reading header: %w", name, err)
+	}
+
+	var records []csvRecord
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		record := make(csvRecord, len(header))
+		for i, column := range header {
+			if i < len(row) {
+				record[column] = row[i]
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// LoadCSV reads the CSV dump from the PokeAPI repository (data/v2/csv).
+func LoadCSV(dir string) (*Catalog, error) {
+	// 1. Type names by id
+	typeRows, err := readCSV(dir, "types.csv")
+	if err != nil {
+		return nil, err
+	}
+	typeNames := make(map[int]string, len(typeRows))
+	for _, row := range typeRows {
+		id, err := row.int("id")
+		if err != nil {
+			return nil, fmt.Errorf("types.csv: %w", err)
+		}
+		typeNames[id] = row["identifier"]
+	}
+
+	// 2. Generations, named after their main region
+	generations, err := loadCSVGenerations(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Species identifiers
+	speciesRows, err := readCSV(dir, "pokemon_species.csv")
+	if err != nil {
+		return nil, err
+	}
+	species := make(map[int]*models.Species, len(speciesRows))
+	for _, row := range speciesRows {
+		id, err := row.int("id")
+		if err != nil {
+			return nil, fmt.Errorf("pokemon_species.csv: %w", err)
+		}
+		s := &models.Species{ID: id, Name: row["identifier"]}
+		if s.GenerationID, err = row.optionalInt("generation_id"); err != nil {
+			return nil, fmt.Errorf("pokemon_species.csv: %w", err)
+		}
+		if row["gender_rate"] != "" {
+			rate, err := row.int("gender_rate")
+			if err != nil {
+				return nil, fmt.Errorf("pokemon_species.csv: %w", err)
+			}
+			s.GenderRate = &rate
+		}
+		if s.ChainID, err = row.optionalInt("evolution_chain_id"); err != nil {
+			return nil, fmt.Errorf("pokemon_species.csv: %w", err)
+		}
+		evolvesFrom, err := row.optionalInt("evolves_from_species_id")
+		if err != nil {
+			return nil, fmt.Errorf("pokemon_species.csv: %w", err)
+		}
+		if evolvesFrom > 0 {
+			s.EvolvesFromID = &evolvesFrom
+		}
+		species[id] = s
+	}
+
+	// 4. Measurements come from the default form of each species
+	pokemonRows, err := readCSV(dir, "pokemon.csv")
+	if err != nil {
+		return nil, err
+	}
+	defaultForm := make(map[int]*models.Species) // pokemon id -> species
+	varieties := make(map[int]*models.Species)   // Every pokemon id, default or not
+	for _, row := range pokemonRows {
+		pokemonID, err := row.int("id")
+		if err != nil {
+			return nil, fmt.Errorf("pokemon.csv: %w", err)
+		}
+		speciesID, err := row.int("species_id")
+		if err != nil {
+			return nil, fmt.Errorf("pokemon.csv: %w", err)
+		}
+		s, ok := species[speciesID]
+		if !ok {
+			continue
+		}
+		varieties[pokemonID] = s
+		if row["is_default"] != "1" {
+			continue
+		}
+		if s.Height, err = row.int("height"); err != nil {
+			return nil, fmt.Errorf("pokemon.csv: %w", err)
+		}
+		if s.Weight, err = row.int("weight"); err != nil {
+			return nil, fmt.Errorf("pokemon.csv: %w", err)
+		}
+		defaultForm[pokemonID] = s
+	}
+
+	// 5. Types, by slot
+	pokemonTypeRows, err := readCSV(dir, "pokemon_types.csv")
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range pokemonTypeRows {
+		pokemonID, err := row.int("pokemon_id")
+		if err != nil {
+			return nil, fmt.Errorf("pokemon_types.csv: %w", err)
+		}
+		s, ok := defaultForm[pokemonID]
+		if !ok {
+			continue
+		}
+		typeID, err := row.int("type_id")
+		if err != nil {
+			return nil, fmt.Errorf("pokemon_types.csv: %w", err)
+		}
+		switch row["slot"] {
+		case "1":
+			s.Types.Primary = models.PokemonType(typeNames[typeID])
+		case "2":
+			s.Types.Secondary = models.PokemonType(typeNames[typeID])
+		}
+	}
+
+	// 6. Base stats
+	statRows, err := readCSV(dir, "stats.csv")
+	if err != nil {
+		return nil, err
+	}
+	statNames := make(map[int]string, len(statRows))
+	for _, row := range statRows {
+		id, err := row.int("id")
+		if err != nil {
+			return nil, fmt.Errorf("stats.csv: %w", err)
+		}
+		statNames[id] = row["identifier"]
+	}
+
+	pokemonStatRows, err := readCSV(dir, "pokemon_stats.csv")
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range pokemonStatRows {
+		pokemonID, err := row.int("pokemon_id")
+		if err != nil {
+			return nil, fmt.Errorf("pokemon_stats.csv: %w", err)
+		}
+		s, ok := defaultForm[pokemonID]
+		if !ok {
+			continue
+		}
+		statID, err := row.int("stat_id")
+		if err != nil {
+			return nil, fmt.Errorf("pokemon_stats.csv: %w", err)
+		}
+		value, err := row.int("base_stat")
+		if err != nil {
+			return nil, fmt.Errorf("pokemon_stats.csv: %w", err)
+		}
+		setBaseStat(s, statNames[statID], value)
+	}
+
+	// 7. Abilities, by slot
+	abilityRows, err := readCSV(dir, "abilities.csv")
+	if err != nil {
+		return nil, err
+	}
+	abilityNames := make(map[int]string, len(abilityRows))
+	for _, row := range abilityRows {
+		id, err := row.int("id")
+		if err != nil {
+			return nil, fmt.Errorf("abilities.csv: %w", err)
+		}
+		abilityNames[id] = row["identifier"]
+	}
+
+	pokemonAbilityRows, err := readCSV(dir, "pokemon_abilities.csv")
+	if err != nil {
+		return nil, err
+	}
+	abilities := make(map[*models.Species][]abilitySlot)
+	for _, row := range pokemonAbilityRows {
+		pokemonID, err := row.int("pokemon_id")
+		if err != nil {
+			return nil, fmt.Errorf("pokemon_abilities.csv: %w", err)
+		}
+		s, ok := defaultForm[pokemonID]
+		if !ok {
+			continue
+		}
+		abilityID, err := row.int("ability_id")
+		if err != nil {
+			return nil, fmt.Errorf("pokemon_abilities.csv: %w", err)
+		}
+		slot, err := row.int("slot")
+		if err != nil {
+			return nil, fmt.Errorf("pokemon_abilities.csv: %w", err)
+		}
+		abilities[s] = append(abilities[s], abilitySlot{slot, abilityNames[abilityID]})
+	}
+	for s, slots := range abilities {
+		s.Abilities = sortedAbilities(slots)
+	}
+
+	// 8. Alternate forms
+	if err := loadCSVForms(dir, varieties, defaultForm); err != nil {
+		return nil, err
+	}
+
+	// 9. Evolutions
+	if err := loadCSVEvolutions(dir, species); err != nil {
+		return nil, err
+	}
+
+	catalog := newCatalog(species)
+	catalog.Generations = generations
+	return catalog, nil
+}
+
+// loadCSVForms records the forms each species can be caught in. Battle-only forms
+// such as megas are skipped, and so is the species' default form.
+func loadCSVForms(dir string, varieties, defaultForm map[int]*models.Species) error {
+	formRows, err := readCSV(dir, "pokemon_forms.csv")
+	if err != nil {
+		return err
+	}
+	for _, row := range formRows {
+		pokemonID, err := row.int("pokemon_id")
+		if err != nil {
+			return fmt.Errorf("pokemon_forms.csv: %w", err)
+		}
+		s, ok := varieties[pokemonID]
+		if !ok || row["form_identifier"] == "" || row["is_battle_only"] == "1" {
+			continue
+		}
+		if _, isDefault := defaultForm[pokemonID]; isDefault && row["is_default"] == "1" {
+			continue
+		}
+		addForm(s, row["form_identifier"])
+	}
+	return nil
+}
+
+// loadCSVGenerations reads generations.csv, resolving each main region's identifier
+func loadCSVGenerations(dir string) ([]models.Generation, error) {
+	regionRows, err := readCSV(dir, "regions.csv")
+	if err != nil {
+		return nil, err
+	}
+	regions := make(map[int]string, len(regionRows))
+	for _, row := range regionRows {
+		id, err := row.int("id")
+		if err != nil {
+			return nil, fmt.Errorf("regions.csv: %w", err)
+		}
+		regions[id] = row["identifier"]
+	}
+
+	generationRows, err := readCSV(dir, "generations.csv")
+	if err != nil {
+		return nil, err
+	}
+	generations := make([]models.Generation, 0, len(generationRows))
+	for _, row := range generationRows {
+		id, err := row.int("id")
+		if err != nil {
+			return nil, fmt.Errorf("generations.csv: %w", err)
+		}
+		regionID, err := row.optionalInt("main_region_id")
+		if err != nil {
+			return nil, fmt.Errorf("generations.csv: %w", err)
+		}
+		generations = append(generations, models.Generation{ID: id, Name: row["identifier"], Region: regions[regionID]})
+	}
+	sort.Slice(generations, func(i, j int) bool { return generations[i].ID < generations[j].ID })
+	return generations, nil
+}
+
+// csvOtherConditions are pokemon_evolution.csv columns for requirements the Pokedex doesn't track
+var csvOtherConditions = []string{
+	"gender_id", "location_id", "known_move_id", "known_move_type_id", "minimum_beauty",
+	"minimum_affection", "relative_physical_stats", "party_species_id", "party_type_id",
+}
+
+// loadCSVEvolutions attaches each row of pokemon_evolution.csv to the species it evolves from
+func loadCSVEvolutions(dir string, species map[int]*models.Species) error {
+	triggerRows, err := readCSV(dir, "evolution_triggers.csv")
+	if err != nil {
+		return err
+	}
+	triggers := make(map[int]string, len(triggerRows))
+	for _, row := range triggerRows {
+		id, err := row.int("id")
+		if err != nil {
+			return fmt.Errorf("evolution_triggers.csv: %w", err)
+		}
+		triggers[id] = row["identifier"]
+	}
+
+	itemRows, err := readCSV(dir, "items.csv")
+	if err != nil {
+		return err
+	}
+	items := make(map[int]string, len(itemRows))
+	for _, row := range itemRows {
+		id, err := row.int("id")
+		if err != nil {
+			return fmt.Errorf("items.csv: %w", err)
+		}
+		items[id] = row["identifier"]
+	}
+
+	evolutionRows, err := readCSV(dir, "pokemon_evolution.csv")
+	if err != nil {
+		return err
+	}
+	for _, row := range evolutionRows {
+		e, err := csvEvolution(row, triggers, items)
+		if err != nil {
+			return fmt.Errorf("pokemon_evolution.csv: %w", err)
+		}
+		to, ok := species[e.ToSpeciesID]
+		if !ok || to.EvolvesFromID == nil {
+			continue
+		}
+		from, ok := species[*to.EvolvesFromID]
+		if !ok {
+			continue
+		}
+		e.FromSpeciesID = from.ID
+		from.Evolutions = append(from.Evolutions, e)
+	}
+	return nil
+}
+
+func csvEvolution(row csvRecord, triggers, items map[int]string) (models.Evolution, error) {
+	var e models.Evolution
+	var err error
+	if e.ToSpeciesID, err = row.int("evolved_species_id"); err != nil {
+		return e, err
+	}
+	triggerID, err := row.int("evolution_trigger_id")
+	if err != nil {
+		return e, err
+	}
+	e.Trigger = models.EvolutionTrigger(triggers[triggerID])
+
+	if e.MinLevel, err = row.optionalInt("minimum_level"); err != nil {
+		return e, err
+	}
+	if e.MinFriendship, err = row.optionalInt("minimum_happiness"); err != nil {
+		return e, err
+	}
+	if e.TradeSpeciesID, err = row.optionalInt("trade_species_id"); err != nil {
+		return e, err
+	}
+	itemID, err := row.optionalInt("trigger_item_id")
+	if err != nil {
+		return e, err
+	}
+	e.Item = items[itemID]
+	heldItemID, err := row.optionalInt("held_item_id")
+	if err != nil {
+		return e, err
+	}
+	e.HeldItem = items[heldItemID]
+	e.TimeOfDay = row["time_of_day"]
+
+	for _, column := range csvOtherConditions {
+		if row[column] != "" {
+			e.OtherConditions = true
+		}
+	}
+	if row["needs_overworld_rain"] == "1" || row["turn_upside_down"] == "1" {
+		e.OtherConditions = true
+	}
+	return e, nil
+}
+
+// newCatalog flattens the species map into a catalog sorted by Pokedex number
+func newCatalog(species map[int]*models.Species) *Catalog {
+	catalog := &Catalog{Species: make([]models.Species, 0, len(species))}
+	for _, s := range species {
+		catalog.Species = append(catalog.Species, *s)
+	}
+	sort.Slice(catalog.Species, func(i, j int) bool { return catalog.Species[i].ID < catalog.Species[j].ID })
+	return catalog
+}
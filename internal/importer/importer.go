@@ -0,0 +1,114 @@
+// Package importer reads PokeAPI data dumps into the species catalog.
+// Both the CSV tables (data/v2/csv) and the static JSON API (data/api/v2) are supported.
+package importer
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+)
+
+// Catalog is everything extracted from a dump, ready to be stored.
+type Catalog struct {
+	Generations []models.Generation
+	Species     []models.Species
+}
+
+// Validate rejects entries that would break the API, such as missing types.
+func (c *Catalog) Validate() error {
+	generations := make(map[int]bool, len(c.Generations))
+	for _, g := range c.Generations {
+		if g.ID <= 0 || g.Name == "" {
+			return fmt.Errorf("generation %d: missing id or name", g.ID)
+		}
+		generations[g.ID] = true
+	}
+
+	known := make(map[int]bool, len(c.Species))
+	for _, s := range c.Species {
+		known[s.ID] = true
+	}
+
+	for _, s := range c.Species {
+		if s.ID <= 0 || s.Name == "" {
+			return fmt.Errorf("species %d: missing id or name", s.ID)
+		}
+		if !s.Types.Primary.IsValid() {
+			return fmt.Errorf("species %d (%s): invalid primary type %q", s.ID, s.Name, s.Types.Primary)
+		}
+		if s.Types.Secondary != "" && !s.Types.Secondary.IsValid() {
+			return fmt.Errorf("species %d (%s): invalid secondary type %q", s.ID, s.Name, s.Types.Secondary)
+		}
+		if s.Height <= 0 || s.Weight <= 0 {
+			return fmt.Errorf("species %d (%s): height and weight must be positive", s.ID, s.Name)
+		}
+		for _, v := range s.BaseStats.Values() {
+			if v <= 0 {
+				return fmt.Errorf("species %d (%s): base stats must be positive", s.ID, s.Name)
+			}
+		}
+		if len(s.Abilities) == 0 {
+			return fmt.Errorf("species %d (%s): no abilities", s.ID, s.Name)
+		}
+		if s.GenderRate != nil && (*s.GenderRate < models.GenderRateGenderless || *s.GenderRate > models.GenderRateFemaleOnly) {
+			return fmt.Errorf("species %d (%s): gender rate %d out of range", s.ID, s.Name, *s.GenderRate)
+		}
+		if s.GenerationID != 0 && !generations[s.GenerationID] {
+			return fmt.Errorf("species %d (%s): unknown generation %d", s.ID, s.Name, s.GenerationID)
+		}
+		if s.EvolvesFromID != nil && !known[*s.EvolvesFromID] {
+			return fmt.Errorf("species %d (%s): evolves from unknown species %d", s.ID, s.Name, *s.EvolvesFromID)
+		}
+		for _, e := range s.Evolutions {
+			if !known[e.ToSpeciesID] {
+				return fmt.Errorf("species %d (%s): evolves into unknown species %d", s.ID, s.Name, e.ToSpeciesID)
+			}
+		}
+	}
+	return nil
+}
+
+// setBaseStat stores one base stat by its PokeAPI identifier. Other stats,
+// such as accuracy and evasion, are ignored.
+func setBaseStat(s *models.Species, stat string, value int) {
+	switch stat {
+	case "hp":
+		s.BaseStats.HP = value
+	case "attack":
+		s.BaseStats.Attack = value
+	case "defense":
+		s.BaseStats.Defense = value
+	case "special-attack":
+		s.BaseStats.SpAttack = value
+	case "special-defense":
+		s.BaseStats.SpDefense = value
+	case "speed":
+		s.BaseStats.Speed = value
+	}
+}
+
+// addForm records an alternate form once, keeping Forms sorted
+func addForm(s *models.Species, form string) {
+	i, found := slices.BinarySearch(s.Forms, form)
+	if !found {
+		s.Forms = slices.Insert(s.Forms, i, form)
+	}
+}
+
+// abilitySlot is an ability with its slot; the hidden ability uses slot 3
+type abilitySlot struct {
+	slot int
+	name string
+}
+
+// sortedAbilities orders abilities by slot so the hidden ability comes last
+func sortedAbilities(slots []abilitySlot) []string {
+	sort.SliceStable(slots, func(i, j int) bool { return slots[i].slot < slots[j].slot })
+	names := make([]string, len(slots))
+	for i, a := range slots {
+		names[i] = a.name
+	}
+	return names
+}
@@ -0,0 +1,304 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+)
+
+// namedResource is PokeAPI's {"name": ..., "url": ...} reference
+type namedResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// id extracts the trailing id from a resource URL like ".../pokemon-species/25/"
+func (r namedResource) id() (int, error) {
+	parts := strings.Split(strings.TrimSuffix(r.URL, "/"), "/")
+	return strconv.Atoi(parts[len(parts)-1])
+}
+
+type speciesJSON struct {
+	ID         int `json:"id"`
+	GenderRate int `json:"gender_rate"`
+}
+
+type pokemonFormJSON struct {
+	FormName     string        `json:"form_name"`
+	IsDefault    bool          `json:"is_default"`
+	IsBattleOnly bool          `json:"is_battle_only"`
+	Pokemon      namedResource `json:"pokemon"`
+}
+
+type pokemonJSON struct {
+	ID        int           `json:"id"`
+	Name      string        `json:"name"`
+	Height    int           `json:"height"`
+	Weight    int           `json:"weight"`
+	IsDefault bool          `json:"is_default"`
+	Species   namedResource `json:"species"`
+	Types     []struct {
+		Slot int           `json:"slot"`
+		Type namedResource `json:"type"`
+	} `json:"types"`
+	Stats []struct {
+		BaseStat int           `json:"base_stat"`
+		Stat     namedResource `json:"stat"`
+	} `json:"stats"`
+	Abilities []struct {
+		Slot    int           `json:"slot"`
+		Ability namedResource `json:"ability"`
+	} `json:"abilities"`
+}
+
+// evolutionDetailJSON is one way of evolving. Requirements the Pokedex
+// doesn't track are only checked for presence.
+type evolutionDetailJSON struct {
+	Trigger        namedResource  `json:"trigger"`
+	Item           *namedResource `json:"item"`
+	HeldItem       *namedResource `json:"held_item"`
+	TradeSpecies   *namedResource `json:"trade_species"`
+	MinLevel       *int           `json:"min_level"`
+	MinHappiness   *int           `json:"min_happiness"`
+	TimeOfDay      string         `json:"time_of_day"`
+	NeedsRain      bool           `json:"needs_overworld_rain"`
+	TurnUpsideDown bool           `json:"turn_upside_down"`
+
+	Gender                *int           `json:"gender"`
+	Location              *namedResource `json:"location"`
+	KnownMove             *namedResource `json:"known_move"`
+	KnownMoveType         *namedResource `json:"known_move_type"`
+	MinBeauty             *int           `json:"min_beauty"`
+	MinAffection          *int           `json:"min_affection"`
+	RelativePhysicalStats *int           `json:"relative_physical_stats"`
+	PartySpecies          *namedResource `json:"party_species"`
+	PartyType             *namedResource `json:"party_type"`
+}
+
+func (d evolutionDetailJSON) otherConditions() bool {
+	return d.NeedsRain || d.TurnUpsideDown || d.Gender != nil || d.Location != nil ||
+		d.KnownMove != nil || d.KnownMoveType != nil || d.MinBeauty != nil || d.MinAffection != nil ||
+		d.RelativePhysicalStats != nil || d.PartySpecies != nil || d.PartyType != nil
+}
+
+// chainLinkJSON is a node of an evolution-chain resource
+type chainLinkJSON struct {
+	Species          namedResource         `json:"species"`
+	EvolutionDetails []evolutionDetailJSON `json:"evolution_details"`
+	EvolvesTo        []chainLinkJSON       `json:"evolves_to"`
+}
+
+type generationJSON struct {
+	ID             int             `json:"id"`
+	Name           string          `json:"name"`
+	MainRegion     namedResource   `json:"main_region"`
+	PokemonSpecies []namedResource `json:"pokemon_species"`
+}
+
+type evolutionChainJSON struct {
+	ID    int           `json:"id"`
+	Chain chainLinkJSON `json:"chain"`
+}
+
+// readJSONResources decodes every <dir>/<resource>/<id>/index.json file
+func readJSONResources[T any](dir, resource string) ([]T, error) {
+	files, err := filepath.Glob(filepath.Join(dir, resource, "*", "index.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no %s resources found under %s", resource, dir)
+	}
+
+	items := make([]T, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var item T
+		if err := json.Unmarshal(data, &item); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// LoadJSON reads the static API dump from the PokeAPI api-data repository
+// (data/api/v2), where each resource lives at <resource>/<id>/index.json.
+func LoadJSON(dir string) (*Catalog, error) {
+	pokemon, err := readJSONResources[pokemonJSON](dir, "pokemon")
+	if err != nil {
+		return nil, err
+	}
+
+	species := make(map[int]*models.Species)
+	varietyOf := make(map[int]int) // pokemon id -> species id, default or not
+	isDefault := make(map[int]bool)
+	for _, p := range pokemon {
+		speciesID, err := p.Species.id()
+		if err != nil {
+			return nil, fmt.Errorf("pokemon %d: species url: %w", p.ID, err)
+		}
+		varietyOf[p.ID] = speciesID
+		if !p.IsDefault {
+			continue
+		}
+		isDefault[p.ID] = true
+
+		s := &models.Species{
+			ID:     speciesID,
+			Name:   p.Species.Name,
+			Height: p.Height,
+			Weight: p.Weight,
+		}
+		for _, t := range p.Types {
+			switch t.Slot {
+			case 1:
+				s.Types.Primary = models.PokemonType(t.Type.Name)
+			case 2:
+				s.Types.Secondary = models.PokemonType(t.Type.Name)
+			}
+		}
+		for _, stat := range p.Stats {
+			setBaseStat(s, stat.Stat.Name, stat.BaseStat)
+		}
+		slots := make([]abilitySlot, len(p.Abilities))
+		for i, a := range p.Abilities {
+			slots[i] = abilitySlot{a.Slot, a.Ability.Name}
+		}
+		s.Abilities = sortedAbilities(slots)
+		species[speciesID] = s
+	}
+
+	speciesResources, err := readJSONResources[speciesJSON](dir, "pokemon-species")
+	if err != nil {
+		return nil, err
+	}
+	for _, sp := range speciesResources {
+		if s, ok := species[sp.ID]; ok {
+			rate := sp.GenderRate
+			s.GenderRate = &rate
+		}
+	}
+
+	// Battle-only forms such as megas are skipped, and so is the species' default form
+	forms, err := readJSONResources[pokemonFormJSON](dir, "pokemon-form")
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range forms {
+		pokemonID, err := f.Pokemon.id()
+		if err != nil {
+			return nil, fmt.Errorf("form %s: pokemon url: %w", f.FormName, err)
+		}
+		s, ok := species[varietyOf[pokemonID]]
+		if !ok || f.FormName == "" || f.IsBattleOnly {
+			continue
+		}
+		if isDefault[pokemonID] && f.IsDefault {
+			continue
+		}
+		addForm(s, f.FormName)
+	}
+
+	generationResources, err := readJSONResources[generationJSON](dir, "generation")
+	if err != nil {
+		return nil, err
+	}
+	generations := make([]models.Generation, 0, len(generationResources))
+	for _, g := range generationResources {
+		generations = append(generations, models.Generation{ID: g.ID, Name: g.Name, Region: g.MainRegion.Name})
+		for _, ref := range g.PokemonSpecies {
+			speciesID, err := ref.id()
+			if err != nil {
+				return nil, fmt.Errorf("generation %d: species url: %w", g.ID, err)
+			}
+			if s, ok := species[speciesID]; ok {
+				s.GenerationID = g.ID
+			}
+		}
+	}
+	sort.Slice(generations, func(i, j int) bool { return generations[i].ID < generations[j].ID })
+
+	chains, err := readJSONResources[evolutionChainJSON](dir, "evolution-chain")
+	if err != nil {
+		return nil, err
+	}
+	for _, chain := range chains {
+		if err := linkEvolutions(species, chain.ID, chain.Chain); err != nil {
+			return nil, fmt.Errorf("evolution chain %d: %w", chain.ID, err)
+		}
+	}
+
+	catalog := newCatalog(species)
+	catalog.Generations = generations
+	return catalog, nil
+}
+
+// linkEvolutions walks a chain from link down, recording the chain id, each
+// species' predecessor and the ways it evolves. Species missing from the
+// catalog are skipped along with their evolutions.
+func linkEvolutions(species map[int]*models.Species, chainID int, link chainLinkJSON) error {
+	fromID, err := link.Species.id()
+	if err != nil {
+		return err
+	}
+	from, ok := species[fromID]
+	if !ok {
+		return nil
+	}
+	from.ChainID = chainID
+
+	for _, next := range link.EvolvesTo {
+		toID, err := next.Species.id()
+		if err != nil {
+			return err
+		}
+		to, ok := species[toID]
+		if !ok {
+			continue
+		}
+		to.EvolvesFromID = &from.ID
+
+		for _, d := range next.EvolutionDetails {
+			e := models.Evolution{
+				FromSpeciesID:   from.ID,
+				ToSpeciesID:     toID,
+				Trigger:         models.EvolutionTrigger(d.Trigger.Name),
+				TimeOfDay:       d.TimeOfDay,
+				OtherConditions: d.otherConditions(),
+			}
+			if d.MinLevel != nil {
+				e.MinLevel = *d.MinLevel
+			}
+			if d.MinHappiness != nil {
+				e.MinFriendship = *d.MinHappiness
+			}
+			if d.Item != nil {
+				e.Item = d.Item.Name
+			}
+			if d.HeldItem != nil {
+				e.HeldItem = d.HeldItem.Name
+			}
+			if d.TradeSpecies != nil {
+				if e.TradeSpeciesID, err = d.TradeSpecies.id(); err != nil {
+					return err
+				}
+			}
+			from.Evolutions = append(from.Evolutions, e)
+		}
+
+		if err := linkEvolutions(species, chainID, next); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// writerMailer writes messages out instead of sending them, for development and tests.
+type writerMailer struct {
+	mu   sync.Mutex
+	w    io.Writer
+	from string
+}
+
+// NewWriterMailer writes every message to w, followed by a separator line.
+func NewWriterMailer(w io.Writer, from string) Mailer {
+	return &writerMailer{w: w, from: from}
+}
+
+// NewFileMailer appends every message to the file at path, or to stdout when path is empty.
+func NewFileMailer(path, from string) (Mailer, error) {
+	if path == "" {
+		return NewWriterMailer(os.Stdout, from), nil
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening mail file: %w", err)
+	}
+	return NewWriterMailer(f, from), nil
+}
+
+func (m *writerMailer) Send(ctx context.Context, msg Message) error {
+	data, err := compose(m.from, msg)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, err := m.w.Write(data); err != nil {
+		return err
+	}
+	_, err = io.WriteString(m.w, "\r\n----\r\n")
+	return err
+}
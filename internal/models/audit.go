@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// AuditAction names what a staff member did.
+type AuditAction string
+
+const (
+	AuditListUsers      AuditAction = "list_users"
+	AuditViewCollection AuditAction = "view_collection"
+	AuditDisableUser    AuditAction = "disable_user"
+	AuditEnableUser     AuditAction = "enable_user"
+	AuditForceLogout    AuditAction = "force_logout"
+	// AuditDenied is an action refused because the target ranks too high;
+	// Details["action"] names what was tried.
+	AuditDenied AuditAction = "denied"
+)
+
+// AuditEntry records one admin action. ActorID and TargetUserID become nil
+// when those accounts are deleted.
+type AuditEntry struct {
+	ID           int            `json:"id"`
+	ActorID      *int           `json:"actor_id"`
+	Action       AuditAction    `json:"action"`
+	TargetUserID *int           `json:"target_user_id,omitempty"`
+	Details      map[string]any `json:"details,omitempty"` // e.g. the search behind a list_users entry
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+// AuditLogOptions filters the audit log, newest first. Zero values match everything.
+type AuditLogOptions struct {
+	ActorID      int
+	TargetUserID int
+	Action       AuditAction
+
+	Limit  int
+	Cursor string // Opaque value from a previous page's NextCursor
+}
+
+// AuditLogPage is one page of the audit log.
+type AuditLogPage struct {
+	Data       []AuditEntry `json:"data"`
+	NextCursor string       `json:"next_cursor,omitempty"` // Empty on the last page
+}
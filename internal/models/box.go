@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// MaxBoxSlots is how many Pokemon fit in one box.
+const MaxBoxSlots = 30
+
+// Box is a named storage box with slots 1..MaxBoxSlots.
+type Box struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	Name      string    `json:"name"`
+	Count     int       `json:"count"` // Occupied slots
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Pokemon are the contents in slot order; only filled when fetching a single box.
+	Pokemon []Pokemon `json:"pokemon,omitempty"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+type PokemonEventType string
+
+const (
+	EventCaught   PokemonEventType = "caught"
+	EventRenamed  PokemonEventType = "renamed"
+	EventEvolved  PokemonEventType = "evolved"
+	EventTraded   PokemonEventType = "traded"
+	EventReleased PokemonEventType = "released"
+)
+
+// PokemonEvent is one entry in a Pokemon's history. Species and nickname are
+// as they were right after the event.
+type PokemonEvent struct {
+	ID            int                 `json:"id"`
+	PokemonID     int                 `json:"pokemon_id"`
+	UserID        int                 `json:"user_id"`                   // Owner when it happened
+	RelatedUserID *int                `json:"related_user_id,omitempty"` // Previous owner of a traded Pokemon
+	Type          PokemonEventType    `json:"type"`
+	PokedexID     int                 `json:"pokedex_id"`
+	Name          string              `json:"name"`
+	Nickname      string              `json:"nickname"`
+	Details       PokemonEventDetails `json:"details"`
+	CreatedAt     time.Time           `json:"created_at"`
+}
+
+// PokemonEventDetails holds what changed; only the fields for the event's type are set.
+type PokemonEventDetails struct {
+	Ball           string `json:"ball,omitempty"`            // caught
+	CaughtLocation string `json:"caught_location,omitempty"` // caught
+	OldNickname    string `json:"old_nickname,omitempty"`    // renamed
+	FromPokedexID  int    `json:"from_pokedex_id,omitempty"` // evolved
+	FromName       string `json:"from_name,omitempty"`       // evolved
+	TradeID        int    `json:"trade_id,omitempty"`        // traded, or evolved on a trade
+}
+
+// NewPokemonEvent describes something that just happened to p.
+func NewPokemonEvent(p *Pokemon, t PokemonEventType, details PokemonEventDetails) PokemonEvent {
+	return PokemonEvent{
+		PokemonID: p.ID,
+		UserID:    p.UserID,
+		Type:      t,
+		PokedexID: p.PokedexID,
+		Name:      p.Name,
+		Nickname:  p.Nickname,
+		Details:   details,
+	}
+}
+
+// ActivityOptions pages through every event a user took part in, newest first.
+type ActivityOptions struct {
+	UserID int
+
+	Limit  int
+	Cursor string // Opaque value from a previous page's NextCursor
+}
+
+// ActivityPage is one page of a user's activity feed.
+type ActivityPage struct {
+	Data       []PokemonEvent `json:"data"`
+	NextCursor string         `json:"next_cursor,omitempty"` // Empty on the last page
+}
@@ -0,0 +1,40 @@
+package models
+
+// EvolutionTrigger is PokeAPI's evolution trigger identifier.
+type EvolutionTrigger string
+
+const (
+	TriggerLevelUp EvolutionTrigger = "level-up"
+	TriggerUseItem EvolutionTrigger = "use-item"
+	TriggerTrade   EvolutionTrigger = "trade"
+)
+
+// Evolution is one way for a species to evolve. Zero values mean "no requirement".
+type Evolution struct {
+	FromSpeciesID  int              `json:"from_species_id"`
+	ToSpeciesID    int              `json:"to_species_id"`
+	Trigger        EvolutionTrigger `json:"trigger"`
+	MinLevel       int              `json:"min_level,omitempty"`
+	MinFriendship  int              `json:"min_friendship,omitempty"`
+	Item           string           `json:"item,omitempty"`
+	HeldItem       string           `json:"held_item,omitempty"`
+	TradeSpeciesID int              `json:"trade_species_id,omitempty"`
+	TimeOfDay      string           `json:"time_of_day,omitempty"`
+	// OtherConditions marks requirements the Pokedex doesn't track,
+	// such as a location, a known move or the weather.
+	OtherConditions bool `json:"other_conditions"`
+}
+
+// EvolutionNode is a species in an evolution chain and how it's reached.
+type EvolutionNode struct {
+	SpeciesID int             `json:"species_id"`
+	Name      string          `json:"name"`
+	Methods   []Evolution     `json:"methods,omitempty"` // Empty for the chain's root
+	EvolvesTo []EvolutionNode `json:"evolves_to"`
+}
+
+// EvolutionChain is the full family tree, starting from the unevolved species.
+type EvolutionChain struct {
+	ID   int           `json:"id"`
+	Root EvolutionNode `json:"root"`
+}
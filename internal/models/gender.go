@@ -0,0 +1,26 @@
+package models
+
+// Gender of a caught Pokemon.
+type Gender string
+
+const (
+	GenderMale       Gender = "male"
+	GenderFemale     Gender = "female"
+	GenderGenderless Gender = "genderless"
+)
+
+// IsValid reports whether g is one of the known genders.
+func (g Gender) IsValid() bool {
+	switch g {
+	case GenderMale, GenderFemale, GenderGenderless:
+		return true
+	}
+	return false
+}
+
+// Species gender rates, in eighths female as PokeAPI counts them.
+const (
+	GenderRateGenderless = -1
+	GenderRateMaleOnly   = 0
+	GenderRateFemaleOnly = 8
+)
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// LoginAttempts counts recent failed logins for one key, such as an email
+// address or a client IP.
+type LoginAttempts struct {
+	Failures      int
+	LastFailureAt time.Time
+	// PreviousFailureAt is when the failure before the last one happened; nil
+	// when the last one started the count.
+	PreviousFailureAt *time.Time
+}
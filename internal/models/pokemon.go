@@ -2,14 +2,108 @@ package models
 
 import "time"
 
+const (
+	// MaxMoves is how many moves a Pokemon can know at once.
+	MaxMoves = 4
+	// MaxFriendship is the friendship cap; friendship evolutions need 160 or 220.
+	MaxFriendship = 255
+	// MaxTags is how many tags a single Pokemon can carry.
+	MaxTags = 20
+)
+
 type Pokemon struct {
-	ID        int       `json:"id"`
-	UserID    int       `json:"user_id"`
-	PokedexID int       `json:"pokedex_id"`
-	Name      string    `json:"name"`
-	Nickname  string    `json:"nickname,omitempty"`
-	Type      string    `json:"type"`
-	Height    int       `json:"height"`
-	Weight    int       `json:"weight"`
+	ID        int    `json:"id"`
+	UserID    int    `json:"user_id"`
+	PokedexID int    `json:"pokedex_id"`
+	Name      string `json:"name"`
+	Nickname  string `json:"nickname,omitempty"`
+	Types     Types  `json:"types"`
+	Height    int    `json:"height"`
+	Weight    int    `json:"weight"`
+
+	Level   int        `json:"level"`
+	Nature  string     `json:"nature"`
+	Ability string     `json:"ability"`
+	IVs     StatSpread `json:"ivs"`
+	EVs     StatSpread `json:"evs"`
+
+	HeldItem   string   `json:"held_item"`
+	Moves      []string `json:"moves"` // Up to MaxMoves
+	Friendship int      `json:"friendship"`
+
+	// BoxID and BoxSlot place the Pokemon in one of its owner's boxes; nil when unboxed.
+	BoxID   *int     `json:"box_id,omitempty"`
+	BoxSlot int      `json:"box_slot,omitempty"`
+	Tags    []string `json:"tags"`
+
+	// Variant and catch details. Gender is empty for Pokemon recorded before it was
+	// tracked, and Form is empty for the species' default form.
+	Shiny           bool      `json:"shiny"`
+	Gender          Gender    `json:"gender,omitempty"`
+	Form            string    `json:"form,omitempty"` // e.g. "alola"
+	Ball            string    `json:"ball"`
+	OriginalTrainer string    `json:"original_trainer,omitempty"`
+	CaughtAt        time.Time `json:"caught_at"`
+	CaughtLocation  string    `json:"caught_location,omitempty"`
+
+	// Stats are computed from the species' base stats; never stored.
+	Stats *StatSpread `json:"stats,omitempty"`
+
 	CreatedAt time.Time `json:"created_at"`
 }
+
+// PokemonSortField is a column the Pokemon list can be ordered by.
+type PokemonSortField string
+
+const (
+	SortByName      PokemonSortField = "name"
+	SortByPokedexID PokemonSortField = "pokedex_id"
+	SortByWeight    PokemonSortField = "weight"
+	SortByHeight    PokemonSortField = "height"
+	SortByCreatedAt PokemonSortField = "created_at"
+)
+
+// IsValid reports whether the list can be sorted by this field.
+func (f PokemonSortField) IsValid() bool {
+	switch f {
+	case SortByName, SortByPokedexID, SortByWeight, SortByHeight, SortByCreatedAt:
+		return true
+	}
+	return false
+}
+
+// PokemonListOptions filters, sorts and paginates a user's collection.
+// Zero values mean "no filter".
+type PokemonListOptions struct {
+	UserID        int
+	Type          PokemonType // Matches either type slot
+	MinPokedexID  int
+	MaxPokedexID  int
+	NamePrefix    string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	BoxID         int
+	Tag           string
+
+	Shiny           *bool
+	Gender          Gender
+	Form            string
+	Ball            string
+	OriginalTrainer string // Case-insensitive
+	CaughtLocation  string // Case-insensitive
+	CaughtAfter     *time.Time
+	CaughtBefore    *time.Time
+
+	SortBy     PokemonSortField
+	Descending bool
+
+	Limit  int
+	Cursor string // Opaque value from a previous page's NextCursor
+}
+
+// PokemonPage is one page of a Pokemon list.
+type PokemonPage struct {
+	Data       []Pokemon `json:"data"`
+	NextCursor string    `json:"next_cursor,omitempty"` // Empty on the last page
+	Total      int       `json:"total"`                 // Matches across all pages
+}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// PokemonType is one of the 18 canonical elemental types.
+type PokemonType string
+
+const (
+	TypeNormal   PokemonType = "normal"
+	TypeFire     PokemonType = "fire"
+	TypeWater    PokemonType = "water"
+	TypeElectric PokemonType = "electric"
+	TypeGrass    PokemonType = "grass"
+	TypeIce      PokemonType = "ice"
+	TypeFighting PokemonType = "fighting"
+	TypePoison   PokemonType = "poison"
+	TypeGround   PokemonType = "ground"
+	TypeFlying   PokemonType = "flying"
+	TypePsychic  PokemonType = "psychic"
+	TypeBug      PokemonType = "bug"
+	TypeRock     PokemonType = "rock"
+	TypeGhost    PokemonType = "ghost"
+	TypeDragon   PokemonType = "dragon"
+	TypeDark     PokemonType = "dark"
+	TypeSteel    PokemonType = "steel"
+	TypeFairy    PokemonType = "fairy"
+)
+
+// AllPokemonTypes lists every type in the games' canonical order.
+var AllPokemonTypes = []PokemonType{
+	TypeNormal, TypeFire, TypeWater, TypeElectric, TypeGrass, TypeIce,
+	TypeFighting, TypePoison, TypeGround, TypeFlying, TypePsychic, TypeBug,
+	TypeRock, TypeGhost, TypeDragon, TypeDark, TypeSteel, TypeFairy,
+}
+
+// IsValid reports whether t is one of the 18 canonical types.
+func (t PokemonType) IsValid() bool {
+	for _, known := range AllPokemonTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// ParsePokemonType normalizes user input ("Fire", " fire ") and rejects unknown types.
+func ParsePokemonType(s string) (PokemonType, error) {
+	t := PokemonType(strings.ToLower(strings.TrimSpace(s)))
+	if !t.IsValid() {
+		return "", fmt.Errorf("unknown type %q", s)
+	}
+	return t, nil
+}
+
+// Types is a primary type plus an optional secondary one.
+// It marshals to a JSON array, e.g. ["grass","poison"] or ["fire"].
+type Types struct {
+	Primary   PokemonType
+	Secondary PokemonType // Empty for single-typed Pokemon
+}
+
+// List returns the types in slot order.
+func (t Types) List() []PokemonType {
+	if t.Secondary == "" {
+		return []PokemonType{t.Primary}
+	}
+	return []PokemonType{t.Primary, t.Secondary}
+}
+
+// Has reports whether either slot holds the given type.
+func (t Types) Has(pt PokemonType) bool {
+	return t.Primary == pt || t.Secondary == pt
+}
+
+// String renders the typing as "grass/poison".
+func (t Types) String() string {
+	if t.Secondary == "" {
+		return string(t.Primary)
+	}
+	return string(t.Primary) + "/" + string(t.Secondary)
+}
+
+func (t Types) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.List())
+}
+
+// UnmarshalJSON only checks the shape; values are validated by the service layer.
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var list []PokemonType
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	if len(list) == 0 || len(list) > 2 {
+		return fmt.Errorf("types must hold one or two entries, got %d", len(list))
+	}
+
+	*t = Types{Primary: list[0]}
+	if len(list) == 2 {
+		t.Secondary = list[1]
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package models
+
+// DexProgress is how much of the species catalog a user has caught.
+// Each generation has one main region, so the breakdown covers both.
+type DexProgress struct {
+	Owned       int                  `json:"owned"` // Distinct species caught
+	Total       int                  `json:"total"` // Species in the catalog
+	Generations []GenerationProgress `json:"generations"`
+	Missing     SpeciesPage          `json:"missing"`
+}
+
+// GenerationProgress counts one generation. Species imported without
+// generation data are grouped under ID 0.
+type GenerationProgress struct {
+	Generation
+	Owned int `json:"owned"`
+	Total int `json:"total"`
+}
+
+// DexProgressOptions paginates the missing species, optionally within one generation.
+type DexProgressOptions struct {
+	UserID       int
+	GenerationID int
+
+	Limit  int
+	Cursor string // Opaque value from a previous page's NextCursor
+}
+
+// SpeciesPage is one page of species, in Pokedex order.
+type SpeciesPage struct {
+	Data       []Species `json:"data"`
+	NextCursor string    `json:"next_cursor,omitempty"` // Empty on the last page
+	Total      int       `json:"total"`                 // Matches across all pages
+}
@@ -0,0 +1,60 @@
+package models
+
+// Species is a canonical catalog entry, keyed by National Pokedex number.
+// Caught Pokemon take their name, types and measurements from here.
+type Species struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"` // PokeAPI identifier, e.g. "mr-mime"
+	Types  Types  `json:"types"`
+	Height int    `json:"height"` // Decimetres
+	Weight int    `json:"weight"` // Hectograms
+
+	GenerationID int `json:"generation_id,omitempty"`
+
+	// GenderRate is in eighths female, or GenderRateGenderless; nil if the catalog predates it.
+	GenderRate *int     `json:"gender_rate,omitempty"`
+	Forms      []string `json:"forms"` // Alternate forms it can be caught in, e.g. "alola"
+
+	BaseStats StatSpread `json:"base_stats"`
+	Abilities []string   `json:"abilities"` // Slot order, hidden ability last
+
+	EvolvesFromID *int        `json:"evolves_from_id,omitempty"`
+	ChainID       int         `json:"chain_id,omitempty"`
+	Evolutions    []Evolution `json:"evolutions,omitempty"` // Ways this species evolves
+}
+
+// Generation groups the species introduced by one set of games.
+type Generation struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`   // PokeAPI identifier, e.g. "generation-i"
+	Region string `json:"region"` // Main region, e.g. "kanto"
+}
+
+// Genders lists the genders the species can be caught as, or nil if its gender rate is unknown.
+func (s *Species) Genders() []Gender {
+	switch {
+	case s.GenderRate == nil:
+		return nil
+	case *s.GenderRate == GenderRateGenderless:
+		return []Gender{GenderGenderless}
+	case *s.GenderRate == GenderRateMaleOnly:
+		return []Gender{GenderMale}
+	case *s.GenderRate == GenderRateFemaleOnly:
+		return []Gender{GenderFemale}
+	default:
+		return []Gender{GenderMale, GenderFemale}
+	}
+}
+
+// HasForm reports whether form is empty (the default form) or one of the species' forms.
+func (s *Species) HasForm(form string) bool {
+	if form == "" {
+		return true
+	}
+	for _, f := range s.Forms {
+		if f == form {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+package models
+
+// StatSpread holds one value per battle stat. It is used for base stats,
+// IVs, EVs and the computed final stats.
+type StatSpread struct {
+	HP        int `json:"hp"`
+	Attack    int `json:"atk"`
+	Defense   int `json:"def"`
+	SpAttack  int `json:"spa"`
+	SpDefense int `json:"spd"`
+	Speed     int `json:"spe"`
+}
+
+// Values returns the stats in HP, Atk, Def, SpA, SpD, Spe order.
+func (s StatSpread) Values() [6]int {
+	return [6]int{s.HP, s.Attack, s.Defense, s.SpAttack, s.SpDefense, s.Speed}
+}
+
+// Total sums all six stats.
+func (s StatSpread) Total() int {
+	total := 0
+	for _, v := range s.Values() {
+		total += v
+	}
+	return total
+}
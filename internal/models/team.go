@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// MaxTeamSize is the size of a battle party
+const MaxTeamSize = 6
+
+// Team is an ordered party built from the owner's own Pokemon.
+type Team struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	Name      string    `json:"name"`
+	Members   []Pokemon `json:"members"` // In slot order
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
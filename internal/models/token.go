@@ -3,9 +3,22 @@ package models
 import "time"
 
 type RefreshToken struct {
-	ID        int       `json:"-"`
-	UserID    int       `json:"user_id"`
-	TokenHash string    `json:"-"`
-	ExpiresAt time.Time `json:"expires_at"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        int        `json:"-"`
+	UserID    int        `json:"user_id"`
+	TokenHash string     `json:"-"`
+	FamilyID  string     `json:"-"` // All tokens descending from one login share a family
+	ExpiresAt time.Time  `json:"expires_at"`
+	RotatedAt *time.Time `json:"-"` // Set once the token has been exchanged for a new one
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// PasswordResetToken lets a user who forgot their password set a new one, once.
+// Like refresh tokens, only the hash of the emailed token is stored.
+type PasswordResetToken struct {
+	ID        int
+	UserID    int
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
 }
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+type TradeStatus string
+
+const (
+	TradePending   TradeStatus = "pending"
+	TradeAccepted  TradeStatus = "accepted"
+	TradeDeclined  TradeStatus = "declined"
+	TradeCancelled TradeStatus = "cancelled"
+)
+
+// IsValid reports whether s is a known trade status.
+func (s TradeStatus) IsValid() bool {
+	switch s {
+	case TradePending, TradeAccepted, TradeDeclined, TradeCancelled:
+		return true
+	}
+	return false
+}
+
+// Trade offers one of the proposer's Pokemon for one of the recipient's.
+type Trade struct {
+	ID                 int             `json:"id"`
+	ProposerID         int             `json:"proposer_id"`
+	RecipientID        int             `json:"recipient_id"`
+	OfferedPokemonID   int             `json:"offered_pokemon_id"`
+	RequestedPokemonID int             `json:"requested_pokemon_id"`
+	Status             TradeStatus     `json:"status"`
+	CreatedAt          time.Time       `json:"created_at"`
+	ResolvedAt         *time.Time      `json:"resolved_at,omitempty"`
+	Transfers          []TradeTransfer `json:"transfers,omitempty"` // Set once accepted
+}
+
+// TradeTransfer records one Pokemon changing hands. The Pokedex ids differ
+// when the Pokemon evolved on arrival.
+type TradeTransfer struct {
+	PokemonID       int       `json:"pokemon_id"`
+	FromUserID      int       `json:"from_user_id"`
+	ToUserID        int       `json:"to_user_id"`
+	PokedexIDBefore int       `json:"pokedex_id_before"`
+	PokedexIDAfter  int       `json:"pokedex_id_after"`
+	TransferredAt   time.Time `json:"transferred_at"`
+}
@@ -1,7 +1,48 @@
 package models
 
+import "time"
+
 type User struct {
-	ID       int    `json:"id"`
-	Email    string `json:"email"`
-	Password string `json:"-"`
+	ID         int        `json:"id"`
+	Email      string     `json:"email"`
+	Password   string     `json:"-"`
+	Role       Role       `json:"role"`
+	DisabledAt *time.Time `json:"disabled_at,omitempty"` // nil means the account is active
+	VerifiedAt *time.Time `json:"verified_at,omitempty"` // nil until the email address is confirmed
+
+	VerificationSentAt *time.Time `json:"-"` // Last verification email, for throttling resends
+
+	TOTPSecret    string     `json:"-"` // Base32; set at enrollment, before 2FA is confirmed
+	TOTPEnabledAt *time.Time `json:"-"` // nil until the user confirms a first code
+}
+
+// IsDisabled reports whether the account has been switched off.
+func (u *User) IsDisabled() bool {
+	return u.DisabledAt != nil
+}
+
+// IsVerified reports whether the user confirmed their email address.
+func (u *User) IsVerified() bool {
+	return u.VerifiedAt != nil
+}
+
+// HasTwoFactor reports whether login asks for a TOTP code after the password.
+func (u *User) HasTwoFactor() bool {
+	return u.TOTPEnabledAt != nil
+}
+
+// UserListOptions filters the user list for staff, oldest account first.
+type UserListOptions struct {
+	Email    string // Case-insensitive substring of the address
+	Role     Role
+	Disabled *bool
+
+	Limit  int
+	Cursor string // Opaque value from a previous page's NextCursor
+}
+
+// UserPage is one page of the user list.
+type UserPage struct {
+	Data       []User `json:"data"`
+	NextCursor string `json:"next_cursor,omitempty"` // Empty on the last page
 }
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+)
+
+type AuditRepository interface {
+	RecordAudit(ctx context.Context, entry models.AuditEntry) error
+	ListAudit(ctx context.Context, opts models.AuditLogOptions) (*models.AuditLogPage, error)
+}
+
+type postgresAuditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) AuditRepository {
+	return &postgresAuditRepository{db: db}
+}
+
+// auditColumns must stay in sync with scanAudit
+const auditColumns = `id, actor_id, action, target_user_id, details, created_at`
+
+func scanAudit(row rowScanner, e *models.AuditEntry) error {
+	return row.Scan(&e.ID, &e.ActorID, &e.Action, &e.TargetUserID, jsonColumn{&e.Details}, &e.CreatedAt)
+}
+
+// insertAuditEntries appends entries to the audit log; pass the transaction that
+// made the change, so an action can't happen without its entry.
+func insertAuditEntries(ctx context.Context, db execer, entries ...models.AuditEntry) error {
+	query := `
+		INSERT INTO admin_audit_log (actor_id, action, target_user_id, details)
+		VALUES ($1, $2, $3, $4)
+	`
+	for _, e := range entries {
+		details := []byte("{}")
+		if len(e.Details) > 0 {
+			var err error
+			if details, err = json.Marshal(e.Details); err != nil {
+				return err
+			}
+		}
+		if _, err := db.ExecContext(ctx, query, e.ActorID, string(e.Action), e.TargetUserID, details); err != nil {
+			return fmt.Errorf("record %s audit entry error: %w", e.Action, err)
+		}
+	}
+	return nil
+}
+
+// RecordAudit appends an entry for an action that changed nothing, such as a read.
+func (r *postgresAuditRepository) RecordAudit(ctx context.Context, entry models.AuditEntry) error {
+	return insertAuditEntries(ctx, r.db, entry)
+}
+
+// ListAudit pages through the audit log, newest first.
+func (r *postgresAuditRepository) ListAudit(ctx context.Context, opts models.AuditLogOptions) (*models.AuditLogPage, error) {
+	w := &whereBuilder{}
+	if opts.ActorID != 0 {
+		w.add("actor_id = $?", opts.ActorID)
+	}
+	if opts.TargetUserID != 0 {
+		w.add("target_user_id = $?", opts.TargetUserID)
+	}
+	if opts.Action != "" {
+		w.add("action = $?", string(opts.Action))
+	}
+
+	// Ids only grow, so the cursor only needs the last one
+	if opts.Cursor != "" {
+		cursor, err := decodeCursor(opts.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if cursor.SortBy != "audit" {
+			return nil, ErrInvalidCursor
+		}
+		w.add("id < $?", cursor.ID)
+	}
+
+	// Fetch one extra row to find out whether another page exists
+	query := fmt.Sprintf(
+		`SELECT %s FROM admin_audit_log %s ORDER BY id DESC LIMIT %s`,
+		auditColumns, w.sql(), w.placeholder(opts.Limit+1),
+	)
+	rows, err := r.db.QueryContext(ctx, query, w.args...)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []models.AuditEntry
+	for rows.Next() {
+		var e models.AuditEntry
+		if err := scanAudit(rows, &e); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	page := &models.AuditLogPage{Data: entries}
+	if len(entries) > opts.Limit {
+		page.Data = entries[:opts.Limit]
+		page.NextCursor, err = encodeCursor(keysetCursor{SortBy: "audit", Descending: true, ID: page.Data[len(page.Data)-1].ID})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
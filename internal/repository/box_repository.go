@@ -0,0 +1,304 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+)
+
+var (
+	// ErrBoxNotOwned is returned when a target box doesn't exist or belongs to another user.
+	ErrBoxNotOwned = errors.New("box not owned by user")
+	// ErrBoxFull is returned when a Pokemon is moved into a box with no free slot.
+	ErrBoxFull = errors.New("box is full")
+	// ErrSlotTaken is returned when the requested slot holds another Pokemon.
+	ErrSlotTaken = errors.New("slot is taken")
+)
+
+// boxSlotConstraint keeps two Pokemon out of one slot
+const boxSlotConstraint = "pokemons_box_slot_unique"
+
+type BoxRepository interface {
+	CreateBox(ctx context.Context, box *models.Box) error
+	GetBox(ctx context.Context, userID, id int) (*models.Box, error)
+	ListBoxes(ctx context.Context, userID int) ([]models.Box, error)
+	RenameBox(ctx context.Context, box *models.Box) (bool, error)
+	DeleteBox(ctx context.Context, userID, id int) (bool, error)
+	MovePokemon(ctx context.Context, userID, pokemonID, boxID, slot int) (bool, error)
+	SwapPokemon(ctx context.Context, userID, pokemonID, otherID int) (bool, error)
+}
+
+type postgresBoxRepository struct {
+	db *sql.DB
+}
+
+func NewBoxRepository(db *sql.DB) BoxRepository {
+	return &postgresBoxRepository{db: db}
+}
+
+// boxColumns must stay in sync with scanBox
+const boxColumns = `id, user_id, name,
+	(SELECT COUNT(*) FROM pokemons WHERE pokemons.box_id = boxes.id), created_at, updated_at`
+
+func scanBox(row rowScanner, b *models.Box) error {
+	return row.Scan(&b.ID, &b.UserID, &b.Name, &b.Count, &b.CreatedAt, &b.UpdatedAt)
+}
+
+func (r *postgresBoxRepository) CreateBox(ctx context.Context, box *models.Box) error {
+	query := `
+		INSERT INTO boxes (user_id, name)
+		VALUES ($1, $2)
+		RETURNING id, created_at, updated_at
+	`
+	return r.db.QueryRowContext(ctx, query, box.UserID, box.Name).Scan(&box.ID, &box.CreatedAt, &box.UpdatedAt)
+}
+
+// GetBox returns the box with its contents, or nil if it doesn't exist or belongs to someone else
+func (r *postgresBoxRepository) GetBox(ctx context.Context, userID, id int) (*models.Box, error) {
+	query := `SELECT ` + boxColumns + ` FROM boxes WHERE id = $1 AND user_id = $2`
+
+	var box models.Box
+	err := scanBox(r.db.QueryRowContext(ctx, query, id, userID), &box)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+
+	query = `SELECT ` + pokemonColumns + ` FROM pokemons WHERE box_id = $1 ORDER BY box_slot`
+	rows, err := r.db.QueryContext(ctx, query, box.ID)
+	if err != nil {
+		return nil, fmt.Errorf("query contents error: %w", err)
+	}
+	defer rows.Close()
+
+	box.Pokemon = []models.Pokemon{}
+	for rows.Next() {
+		var p models.Pokemon
+		if err := scanPokemon(rows, &p); err != nil {
+			return nil, err
+		}
+		box.Pokemon = append(box.Pokemon, p)
+	}
+	return &box, rows.Err()
+}
+
+func (r *postgresBoxRepository) ListBoxes(ctx context.Context, userID int) ([]models.Box, error) {
+	query := `SELECT ` + boxColumns + ` FROM boxes WHERE user_id = $1 ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	var boxes []models.Box
+	for rows.Next() {
+		var box models.Box
+		if err := scanBox(rows, &box); err != nil {
+			return nil, err
+		}
+		boxes = append(boxes, box)
+	}
+	return boxes, rows.Err()
+}
+
+// RenameBox returns false if no box owned by box.UserID matched
+func (r *postgresBoxRepository) RenameBox(ctx context.Context, box *models.Box) (bool, error) {
+	query := `
+		UPDATE boxes SET name = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND user_id = $3
+		RETURNING ` + boxColumns
+	err := scanBox(r.db.QueryRowContext(ctx, query, box.Name, box.ID, box.UserID), box)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("update error: %w", err)
+	}
+	return true, nil
+}
+
+// DeleteBox unboxes the contents and removes the box. Returns false if nothing matched.
+func (r *postgresBoxRepository) DeleteBox(ctx context.Context, userID, id int) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock first, so nothing is moved in between emptying and deleting
+	if found, err := lockBox(ctx, tx, userID, id); err != nil || !found {
+		return false, err
+	}
+
+	query := `UPDATE pokemons SET box_id = NULL, box_slot = NULL WHERE box_id = $1`
+	if _, err := tx.ExecContext(ctx, query, id); err != nil {
+		return false, fmt.Errorf("empty box error: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM boxes WHERE id = $1`, id); err != nil {
+		return false, fmt.Errorf("delete error: %w", err)
+	}
+
+	return true, tx.Commit()
+}
+
+// lockBox serializes moves into a box. NO KEY UPDATE still lets other
+// transactions reference the box, so it can't deadlock with a swap's FK checks.
+func lockBox(ctx context.Context, tx *sql.Tx, userID, id int) (bool, error) {
+	var lockedID int
+	query := `SELECT id FROM boxes WHERE id = $1 AND user_id = $2 FOR NO KEY UPDATE`
+	err := tx.QueryRowContext(ctx, query, id, userID).Scan(&lockedID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("lock box error: %w", err)
+	}
+	return true, nil
+}
+
+// MovePokemon puts the Pokemon in a box slot. Slot 0 picks the first free slot and
+// box 0 takes the Pokemon out of its box. Returns false if the user has no such Pokemon,
+// and ErrBoxNotOwned, ErrBoxFull or ErrSlotTaken if the move isn't possible.
+func (r *postgresBoxRepository) MovePokemon(ctx context.Context, userID, pokemonID, boxID, slot int) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 1. Lock the target box, so concurrent moves into it take turns
+	if boxID > 0 {
+		found, err := lockBox(ctx, tx, userID, boxID)
+		if err != nil {
+			return false, err
+		}
+		if !found {
+			return false, fmt.Errorf("%w: box %d", ErrBoxNotOwned, boxID)
+		}
+	}
+
+	// 2. Lock the Pokemon
+	var lockedID int
+	query := `SELECT id FROM pokemons WHERE id = $1 AND user_id = $2 FOR UPDATE`
+	err = tx.QueryRowContext(ctx, query, pokemonID, userID).Scan(&lockedID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("lock pokemon error: %w", err)
+	}
+
+	// 3. Pick or check the slot
+	if boxID == 0 {
+		query = `UPDATE pokemons SET box_id = NULL, box_slot = NULL WHERE id = $1`
+		if _, err := tx.ExecContext(ctx, query, pokemonID); err != nil {
+			return false, fmt.Errorf("unbox error: %w", err)
+		}
+		return true, tx.Commit()
+	}
+
+	if slot == 0 {
+		query = `
+			SELECT s FROM generate_series(1, $1::int) s
+			WHERE NOT EXISTS (SELECT 1 FROM pokemons WHERE box_id = $2 AND box_slot = s AND id <> $3)
+			ORDER BY s LIMIT 1
+		`
+		err = tx.QueryRowContext(ctx, query, models.MaxBoxSlots, boxID, pokemonID).Scan(&slot)
+		if err == sql.ErrNoRows {
+			return false, fmt.Errorf("%w: box %d", ErrBoxFull, boxID)
+		}
+		if err != nil {
+			return false, fmt.Errorf("find slot error: %w", err)
+		}
+	} else {
+		var occupant int
+		query = `SELECT id FROM pokemons WHERE box_id = $1 AND box_slot = $2`
+		err = tx.QueryRowContext(ctx, query, boxID, slot).Scan(&occupant)
+		if err != nil && err != sql.ErrNoRows {
+			return false, fmt.Errorf("check slot error: %w", err)
+		}
+		if err == nil && occupant != pokemonID {
+			return false, fmt.Errorf("%w: slot %d holds pokemon %d", ErrSlotTaken, slot, occupant)
+		}
+	}
+
+	// 4. Move
+	query = `UPDATE pokemons SET box_id = $1, box_slot = $2 WHERE id = $3`
+	if _, err := tx.ExecContext(ctx, query, boxID, slot, pokemonID); err != nil {
+		return false, slotError(fmt.Errorf("move error: %w", err))
+	}
+	return true, slotError(tx.Commit())
+}
+
+// SwapPokemon exchanges the places of two of the user's Pokemon; either may be
+// unboxed. Returns false unless the user owns both.
+func (r *postgresBoxRepository) SwapPokemon(ctx context.Context, userID, pokemonID, otherID int) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 1. Lock both in id order, so two swaps of the same pair can't deadlock
+	query := `
+		SELECT id, box_id, box_slot FROM pokemons
+		WHERE id = ANY($1) AND user_id = $2
+		ORDER BY id FOR UPDATE
+	`
+	rows, err := tx.QueryContext(ctx, query, []int{pokemonID, otherID}, userID)
+	if err != nil {
+		return false, fmt.Errorf("lock pokemon error: %w", err)
+	}
+	type placement struct {
+		boxID, slot sql.NullInt64
+	}
+	places := make(map[int]placement, 2)
+	for rows.Next() {
+		var id int
+		var p placement
+		if err := rows.Scan(&id, &p.boxID, &p.slot); err != nil {
+			rows.Close()
+			return false, err
+		}
+		places[id] = p
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+	if len(places) != 2 {
+		return false, nil
+	}
+
+	// 2. Both briefly share a slot, so check uniqueness at commit instead
+	if _, err := tx.ExecContext(ctx, `SET CONSTRAINTS `+boxSlotConstraint+` DEFERRED`); err != nil {
+		return false, fmt.Errorf("defer constraint error: %w", err)
+	}
+	query = `UPDATE pokemons SET box_id = $1, box_slot = $2 WHERE id = $3`
+	for id, other := range map[int]int{pokemonID: otherID, otherID: pokemonID} {
+		p := places[other]
+		if _, err := tx.ExecContext(ctx, query, p.boxID, p.slot, id); err != nil {
+			return false, fmt.Errorf("swap error: %w", err)
+		}
+	}
+
+	// A Pokemon moved into either slot meanwhile only shows up here
+	return true, slotError(tx.Commit())
+}
+
+// slotError turns a violation of the slot constraint, which a concurrent move
+// can cause, into ErrSlotTaken. Other errors pass through unchanged.
+func slotError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == boxSlotConstraint {
+		return fmt.Errorf("%w: %s", ErrSlotTaken, pgErr.Detail)
+	}
+	return err
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+)
+
+// EventRepository reads the Pokemon history. Events are written by the
+// repositories that change Pokemon, in the same transaction as the change.
+type EventRepository interface {
+	ListPokemonEvents(ctx context.Context, pokemonID int) ([]models.PokemonEvent, error)
+	ListActivity(ctx context.Context, opts models.ActivityOptions) (*models.ActivityPage, error)
+}
+
+type postgresEventRepository struct {
+	db *sql.DB
+}
+
+func NewEventRepository(db *sql.DB) EventRepository {
+	return &postgresEventRepository{db: db}
+}
+
+// eventColumns must stay in sync with scanEvent
+const eventColumns = `id, pokemon_id, user_id, related_user_id, event_type, pokedex_id, name, nickname, details, created_at`
+
+func scanEvent(row rowScanner, e *models.PokemonEvent) error {
+	return row.Scan(&e.ID, &e.PokemonID, &e.UserID, &e.RelatedUserID, &e.Type,
+		&e.PokedexID, &e.Name, &e.Nickname, jsonColumn{&e.Details}, &e.CreatedAt)
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// insertPokemonEvents appends events to the history; pass the transaction that made the change.
+func insertPokemonEvents(ctx context.Context, db execer, events ...models.PokemonEvent) error {
+	query := `
+		INSERT INTO pokemon_events (pokemon_id, user_id, related_user_id, event_type, pokedex_id, name, nickname, details)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	for _, e := range events {
+		details, err := json.Marshal(e.Details)
+		if err != nil {
+			return err
+		}
+		_, err = db.ExecContext(ctx, query, e.PokemonID, e.UserID, e.RelatedUserID, string(e.Type),
+			e.PokedexID, e.Name, e.Nickname, details)
+		if err != nil {
+			return fmt.Errorf("record %s event error: %w", e.Type, err)
+		}
+	}
+	return nil
+}
+
+// ListPokemonEvents returns the whole history of a Pokemon, oldest first,
+// including events from its previous owners.
+func (r *postgresEventRepository) ListPokemonEvents(ctx context.Context, pokemonID int) ([]models.PokemonEvent, error) {
+	query := `SELECT ` + eventColumns + ` FROM pokemon_events WHERE pokemon_id = $1 ORDER BY id`
+	rows, err := r.db.QueryContext(ctx, query, pokemonID)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.PokemonEvent
+	for rows.Next() {
+		var e models.PokemonEvent
+		if err := scanEvent(rows, &e); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// ListActivity returns one page of the events a user took part in, newest
+// first. Trades show up for both sides.
+func (r *postgresEventRepository) ListActivity(ctx context.Context, opts models.ActivityOptions) (*models.ActivityPage, error) {
+	w := &whereBuilder{}
+	w.add("(user_id = $? OR related_user_id = $?)", opts.UserID, opts.UserID)
+
+	// Ids only grow, so the cursor only needs the last one
+	if opts.Cursor != "" {
+		cursor, err := decodeCursor(opts.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if cursor.SortBy != "activity" {
+			return nil, ErrInvalidCursor
+		}
+		w.add("id < $?", cursor.ID)
+	}
+
+	// Fetch one extra row to find out whether another page exists
+	query := fmt.Sprintf(
+		`SELECT %s FROM pokemon_events %s ORDER BY id DESC LIMIT %s`,
+		eventColumns, w.sql(), w.placeholder(opts.Limit+1),
+	)
+	rows, err := r.db.QueryContext(ctx, query, w.args...)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.PokemonEvent
+	for rows.Next() {
+		var e models.PokemonEvent
+		if err := scanEvent(rows, &e); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	page := &models.ActivityPage{Data: events}
+	if len(events) > opts.Limit {
+		page.Data = events[:opts.Limit]
+		page.NextCursor, err = encodeCursor(keysetCursor{SortBy: "activity", Descending: true, ID: page.Data[len(page.Data)-1].ID})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+)
+
+// LoginAttemptStore counts failed logins per key. Counts are forgotten once
+// the last failure is older than the store's window.
+type LoginAttemptStore interface {
+	// RecordLoginFailure counts a failure and returns the new count in the same
+	// step, so concurrent callers each see the failures recorded before theirs.
+	RecordLoginFailure(ctx context.Context, key string) (*models.LoginAttempts, error)
+	// RefundLoginFailure takes back one recorded failure, for an attempt that
+	// was charged up front and then succeeded.
+	RefundLoginFailure(ctx context.Context, key string) error
+	ResetLoginAttempts(ctx context.Context, key string) error
+}
+
+// postgresLoginAttemptStore keeps the counts in the database, so every instance sees them.
+type postgresLoginAttemptStore struct {
+	db     *sql.DB
+	window time.Duration
+}
+
+func NewLoginAttemptStore(db *sql.DB, window time.Duration) LoginAttemptStore {
+	return &postgresLoginAttemptStore{db: db, window: window}
+}
+
+// RecordLoginFailure counts a failure atomically, starting over if the last one
+// is outside the window. Expired rows of other keys are pruned on the way.
+func (r *postgresLoginAttemptStore) RecordLoginFailure(ctx context.Context, key string) (*models.LoginAttempts, error) {
+	query := `
+		WITH pruned AS (
+			DELETE FROM login_attempts
+			WHERE last_failure_at <= NOW() - make_interval(secs => $2) AND key <> $1
+		)
+		INSERT INTO login_attempts (key, failures, last_failure_at)
+		VALUES ($1, 1, NOW())
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE
+				WHEN login_attempts.last_failure_at <= NOW() - make_interval(secs => $2) THEN 1
+				ELSE login_attempts.failures + 1
+			END,
+			previous_failure_at = CASE
+				WHEN login_attempts.last_failure_at <= NOW() - make_interval(secs => $2) THEN NULL
+				ELSE login_attempts.last_failure_at
+			END,
+			last_failure_at = NOW()
+		RETURNING failures, last_failure_at, previous_failure_at
+	`
+	var a models.LoginAttempts
+	err := r.db.QueryRowContext(ctx, query, key, r.window.Seconds()).Scan(&a.Failures, &a.LastFailureAt, &a.PreviousFailureAt)
+	if err != nil {
+		return nil, fmt.Errorf("record failure error: %w", err)
+	}
+	return &a, nil
+}
+
+func (r *postgresLoginAttemptStore) RefundLoginFailure(ctx context.Context, key string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE login_attempts SET failures = failures - 1 WHERE key = $1 AND failures > 0`, key)
+	return err
+}
+
+func (r *postgresLoginAttemptStore) ResetLoginAttempts(ctx context.Context, key string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM login_attempts WHERE key = $1`, key)
+	return err
+}
+
+// memoryLoginAttemptStore keeps the counts in this process. Limits only hold
+// per instance, so it suits development and single-instance deployments.
+type memoryLoginAttemptStore struct {
+	mu        sync.Mutex
+	window    time.Duration
+	attempts  map[string]models.LoginAttempts
+	lastSweep time.Time
+}
+
+func NewMemoryLoginAttemptStore(window time.Duration) LoginAttemptStore {
+	return &memoryLoginAttemptStore{window: window, attempts: make(map[string]models.LoginAttempts)}
+}
+
+func (m *memoryLoginAttemptStore) expired(a models.LoginAttempts, now time.Time) bool {
+	return !a.LastFailureAt.After(now.Add(-m.window))
+}
+
+func (m *memoryLoginAttemptStore) RecordLoginFailure(ctx context.Context, key string) (*models.LoginAttempts, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	// Sweep at most once per window, so a spray of keys can't grow the map forever
+	if now.Sub(m.lastSweep) >= m.window {
+		for k, a := range m.attempts {
+			if m.expired(a, now) {
+				delete(m.attempts, k)
+			}
+		}
+		m.lastSweep = now
+	}
+
+	a := m.attempts[key]
+	if m.expired(a, now) {
+		a = models.LoginAttempts{}
+	} else {
+		previous := a.LastFailureAt
+		a.PreviousFailureAt = &previous
+	}
+	a.Failures++
+	a.LastFailureAt = now
+	m.attempts[key] = a
+	return &a, nil
+}
+
+func (m *memoryLoginAttemptStore) RefundLoginFailure(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if a, ok := m.attempts[key]; ok && a.Failures > 0 {
+		a.Failures--
+		m.attempts[key] = a
+	}
+	return nil
+}
+
+func (m *memoryLoginAttemptStore) ResetLoginAttempts(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.attempts, key)
+	return nil
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// MFARepository stores TOTP secrets and recovery codes. The secret lives on the
+// users row, so GetUserByID already returns it.
+type MFARepository interface {
+	SetPendingTOTPSecret(ctx context.Context, userID int, secret string) (bool, error)
+	EnableTOTP(ctx context.Context, userID int, secret string, step int64, recoveryCodeHashes []string) (bool, error)
+	DisableTOTP(ctx context.Context, userID int) error
+	UseTOTPStep(ctx context.Context, userID int, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error)
+}
+
+type postgresMFARepository struct {
+	db *sql.DB
+}
+
+func NewMFARepository(db *sql.DB) MFARepository {
+	return &postgresMFARepository{db: db}
+}
+
+// SetPendingTOTPSecret stores a new secret awaiting confirmation, replacing any
+// earlier unconfirmed one. Returns false if 2FA is already on.
+func (r *postgresMFARepository) SetPendingTOTPSecret(ctx context.Context, userID int, secret string) (bool, error) {
+	query := `
+		UPDATE users SET totp_secret = $2, totp_last_step = NULL
+		WHERE id = $1 AND totp_enabled_at IS NULL
+	`
+	res, err := r.db.ExecContext(ctx, query, userID, secret)
+	if err != nil {
+		return false, fmt.Errorf("update error: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// EnableTOTP turns 2FA on and replaces the user's recovery codes, in one transaction.
+// step is the time step of the confirming code, so it can't be used again to log in.
+// Returns false if the pending secret changed meanwhile or 2FA is already on.
+func (r *postgresMFARepository) EnableTOTP(ctx context.Context, userID int, secret string, step int64, recoveryCodeHashes []string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 1. Enable, but only for the secret the code was checked against
+	query := `
+		UPDATE users SET totp_enabled_at = NOW(), totp_last_step = $3
+		WHERE id = $1 AND totp_secret = $2 AND totp_enabled_at IS NULL
+	`
+	res, err := tx.ExecContext(ctx, query, userID, secret, step)
+	if err != nil {
+		return false, fmt.Errorf("enable totp error: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if n != 1 {
+		return false, nil
+	}
+
+	// 2. Codes from an earlier enrollment stop working
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return false, fmt.Errorf("delete recovery codes error: %w", err)
+	}
+
+	// 3. Store the new codes
+	query = `INSERT INTO mfa_recovery_codes (user_id, code_hash) VALUES ($1, $2)`
+	for _, hash := range recoveryCodeHashes {
+		if _, err := tx.ExecContext(ctx, query, userID, hash); err != nil {
+			return false, fmt.Errorf("insert recovery code error: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit error: %w", err)
+	}
+	return true, nil
+}
+
+// DisableTOTP turns 2FA off and forgets the secret and recovery codes.
+func (r *postgresMFARepository) DisableTOTP(ctx context.Context, userID int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE users SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = NULL WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+		return fmt.Errorf("disable totp error: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("delete recovery codes error: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit error: %w", err)
+	}
+	return nil
+}
+
+// UseTOTPStep records that a code from this time step was accepted. Returns false
+// if a code from this step or a later one was already used, so each code works once.
+func (r *postgresMFARepository) UseTOTPStep(ctx context.Context, userID int, step int64) (bool, error) {
+	query := `
+		UPDATE users SET totp_last_step = $2
+		WHERE id = $1 AND totp_enabled_at IS NOT NULL AND (totp_last_step IS NULL OR totp_last_step < $2)
+	`
+	res, err := r.db.ExecContext(ctx, query, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("update error: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// UseRecoveryCode spends a recovery code. Returns false if it is unknown or already used.
+func (r *postgresMFARepository) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	query := `
+		UPDATE mfa_recovery_codes SET used_at = NOW()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+	res, err := r.db.ExecContext(ctx, query, userID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("update error: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
@@ -3,14 +3,23 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
 )
 
 type PokemonRepository interface {
 	CreatePokemon(ctx context.Context, p *models.Pokemon) error
-	ListPokemonByUserID(ctx context.Context, userID int) ([]models.Pokemon, error)
+	CreatePokemons(ctx context.Context, pokemons []*models.Pokemon) error
+	ListPokemonByUserID(ctx context.Context, opts models.PokemonListOptions) (*models.PokemonPage, error)
+	StreamPokemonByUserID(ctx context.Context, userID int, fn func(p *models.Pokemon) error) error
+	GetPokemonByID(ctx context.Context, userID, id int) (*models.Pokemon, error)
+	UpdatePokemon(ctx context.Context, p *models.Pokemon, events ...models.PokemonEvent) (bool, error)
+	DeletePokemon(ctx context.Context, userID, id int) (bool, error)
+	CountOwnedByGeneration(ctx context.Context, userID int) ([]models.GenerationProgress, error)
+	ListMissingSpecies(ctx context.Context, opts models.DexProgressOptions) (*models.SpeciesPage, error)
 }
 
 type postgresPokemonRepository struct {
@@ -21,22 +30,151 @@ func NewPokemonRepository(db *sql.DB) PokemonRepository {
 	return &postgresPokemonRepository{db: db}
 }
 
+// pokemonColumns must stay in sync with scanPokemon
+const pokemonColumns = `id, user_id, pokedex_id, name, nickname, primary_type::text, COALESCE(secondary_type::text, ''), height, weight,
+	level, nature, ability,
+	iv_hp, iv_attack, iv_defense, iv_sp_attack, iv_sp_defense, iv_speed,
+	ev_hp, ev_attack, ev_defense, ev_sp_attack, ev_sp_defense, ev_speed,
+	held_item, to_jsonb(moves), friendship, box_id, COALESCE(box_slot, 0), to_jsonb(tags),
+	shiny, gender, form, ball, original_trainer, caught_at, caught_location, created_at`
+
+func scanPokemon(row rowScanner, p *models.Pokemon) error {
+	dest := []any{
+		&p.ID, &p.UserID, &p.PokedexID, &p.Name, &p.Nickname,
+		&p.Types.Primary, &p.Types.Secondary, &p.Height, &p.Weight,
+		&p.Level, &p.Nature, &p.Ability,
+	}
+	dest = append(dest, statSpreadFields(&p.IVs)...)
+	dest = append(dest, statSpreadFields(&p.EVs)...)
+	dest = append(dest, &p.HeldItem, jsonColumn{&p.Moves}, &p.Friendship)
+	dest = append(dest, &p.BoxID, &p.BoxSlot, jsonColumn{&p.Tags})
+	dest = append(dest, &p.Shiny, &p.Gender, &p.Form, &p.Ball, &p.OriginalTrainer, &p.CaughtAt, &p.CaughtLocation)
+	return row.Scan(append(dest, &p.CreatedAt)...)
+}
+
+// insertPokemonQuery is shared by CreatePokemon and CreatePokemons. It starts
+// the Pokemon's history with a caught event in the same statement.
+const insertPokemonQuery = `
+	WITH inserted AS (
+		INSERT INTO pokemons (
+			user_id, pokedex_id, name, nickname, primary_type, secondary_type, height, weight,
+			level, nature, ability,
+			iv_hp, iv_attack, iv_defense, iv_sp_attack, iv_sp_defense, iv_speed,
+			ev_hp, ev_attack, ev_defense, ev_sp_attack, ev_sp_defense, ev_speed,
+			held_item, moves, friendship, tags,
+			shiny, gender, form, ball, original_trainer, caught_at, caught_location
+		)
+		VALUES (
+			$1, $2, $3, $4, $5::text::pokemon_type, NULLIF($6, '')::pokemon_type, $7, $8,
+			$9, $10, $11,
+			$12, $13, $14, $15, $16, $17,
+			$18, $19, $20, $21, $22, $23,
+			$24, COALESCE($25::text[], '{}'), $26, COALESCE($27::text[], '{}'),
+			$28, $29, $30, $31, $32, COALESCE($33, CURRENT_TIMESTAMP), $34
+		)
+		RETURNING id, user_id, pokedex_id, name, nickname, ball, caught_location, caught_at, created_at
+	), caught AS (
+		INSERT INTO pokemon_events (pokemon_id, user_id, event_type, pokedex_id, name, nickname, details, created_at)
+		SELECT id, user_id, 'caught', pokedex_id, name, nickname,
+			jsonb_strip_nulls(jsonb_build_object('ball', ball, 'caught_location', NULLIF(caught_location, ''))),
+			created_at
+		FROM inserted
+	)
+	SELECT id, caught_at, created_at FROM inserted
+`
+
+func pokemonInsertArgs(p *models.Pokemon) []any {
+	args := []any{
+		p.UserID, p.PokedexID, p.Name, p.Nickname,
+		string(p.Types.Primary), string(p.Types.Secondary), p.Height, p.Weight,
+		p.Level, p.Nature, p.Ability,
+	}
+	args = append(args, statSpreadArgs(p.IVs)...)
+	args = append(args, statSpreadArgs(p.EVs)...)
+	args = append(args, p.HeldItem, p.Moves, p.Friendship, p.Tags)
+	return append(args, p.Shiny, string(p.Gender), p.Form, p.Ball, p.OriginalTrainer, nullTime(p.CaughtAt), p.CaughtLocation)
+}
+
 func (r *postgresPokemonRepository) CreatePokemon(ctx context.Context, p *models.Pokemon) error {
-	query := `
-		INSERT INTO pokemons (user_id, pokedex_id, name, nickname, type, height, weight)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, created_at
-	`
-	return r.db.QueryRowContext(
-		ctx, query,
-		p.UserID, p.PokedexID, p.Name, p.Nickname, p.Type, p.Height, p.Weight,
-	).Scan(&p.ID, &p.CreatedAt)
+	return r.db.QueryRowContext(ctx, insertPokemonQuery, pokemonInsertArgs(p)...).Scan(&p.ID, &p.CaughtAt, &p.CreatedAt)
 }
 
-func (r *postgresPokemonRepository) ListPokemonByUserID(ctx context.Context, userID int) ([]models.Pokemon, error) {
-	query := `SELECT id, user_id, pokedex_id, name, nickname, type, height, weight, created_at FROM pokemons WHERE user_id = $1`
+// CreatePokemons inserts all Pokemon in one transaction, so either every one is saved or none.
+func (r *postgresPokemonRepository) CreatePokemons(ctx context.Context, pokemons []*models.Pokemon) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-	rows, err := r.db.QueryContext(ctx, query, userID)
+	stmt, err := tx.PrepareContext(ctx, insertPokemonQuery)
+	if err != nil {
+		return fmt.Errorf("preparing insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, p := range pokemons {
+		if err := stmt.QueryRowContext(ctx, pokemonInsertArgs(p)...).Scan(&p.ID, &p.CaughtAt, &p.CreatedAt); err != nil {
+			return fmt.Errorf("inserting pokemon %d: %w", i+1, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ListPokemonByUserID returns one page of the user's Pokemon using keyset
+// pagination on (sort column, id), so deep pages stay as cheap as the first.
+func (r *postgresPokemonRepository) ListPokemonByUserID(ctx context.Context, opts models.PokemonListOptions) (*models.PokemonPage, error) {
+	// The sort column is interpolated into SQL, so never trust it blindly
+	if !opts.SortBy.IsValid() {
+		return nil, fmt.Errorf("unsupported sort field %q", opts.SortBy)
+	}
+	sortCol := string(opts.SortBy)
+
+	w := &whereBuilder{}
+	w.add("user_id = $?", opts.UserID)
+	applyPokemonFilters(w, opts)
+
+	// 1. Total matches, ignoring the cursor
+	var total int
+	countQuery := `SELECT COUNT(*) FROM pokemons ` + w.sql()
+	if err := r.db.QueryRowContext(ctx, countQuery, w.args...).Scan(&total); err != nil {
+		return nil, fmt.Errorf("count error: %w", err)
+	}
+
+	// 2. Resume after the last row of the previous page
+	if opts.Cursor != "" {
+		cursor, err := decodeCursor(opts.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if cursor.SortBy != sortCol || cursor.Descending != opts.Descending {
+			return nil, ErrInvalidCursor
+		}
+		value, err := pokemonCursorValue(opts.SortBy, cursor.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		op := ">"
+		if opts.Descending {
+			op = "<"
+		}
+		w.add(fmt.Sprintf("(%s, id) %s ($?, $?)", sortCol, op), value, cursor.ID)
+	}
+
+	dir := "ASC"
+	if opts.Descending {
+		dir = "DESC"
+	}
+
+	// 3. Fetch one extra row to find out whether another page exists
+	query := fmt.Sprintf(
+		`SELECT %s FROM pokemons %s ORDER BY %s %s, id %s LIMIT %s`,
+		pokemonColumns, w.sql(), sortCol, dir, dir, w.placeholder(opts.Limit+1),
+	)
+
+	rows, err := r.db.QueryContext(ctx, query, w.args...)
 	if err != nil {
 		return nil, fmt.Errorf("query error: %w", err)
 	}
@@ -45,13 +183,326 @@ func (r *postgresPokemonRepository) ListPokemonByUserID(ctx context.Context, use
 	var pokemons []models.Pokemon
 	for rows.Next() {
 		var p models.Pokemon
-		if err := rows.Scan(
-			&p.ID, &p.UserID, &p.PokedexID, &p.Name, &p.Nickname,
-			&p.Type, &p.Height, &p.Weight, &p.CreatedAt,
-		); err != nil {
+		if err := scanPokemon(rows, &p); err != nil {
 			return nil, err
 		}
 		pokemons = append(pokemons, p)
 	}
-	return pokemons, nil
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	page := &models.PokemonPage{Data: pokemons, Total: total}
+	if len(pokemons) > opts.Limit {
+		page.Data = pokemons[:opts.Limit]
+		last := page.Data[len(page.Data)-1]
+
+		value, err := json.Marshal(pokemonSortValue(opts.SortBy, &last))
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor, err = encodeCursor(keysetCursor{
+			SortBy:     sortCol,
+			Descending: opts.Descending,
+			Value:      value,
+			ID:         last.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return page, nil
+}
+
+// StreamPokemonByUserID calls fn for each of the user's Pokemon in id order,
+// reading rows as they arrive instead of collecting them. An error from fn stops the scan.
+func (r *postgresPokemonRepository) StreamPokemonByUserID(ctx context.Context, userID int, fn func(p *models.Pokemon) error) error {
+	query := `SELECT ` + pokemonColumns + ` FROM pokemons WHERE user_id = $1 ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p models.Pokemon
+		if err := scanPokemon(rows, &p); err != nil {
+			return err
+		}
+		if err := fn(&p); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// applyPokemonFilters adds a condition for every filter that is set
+func applyPokemonFilters(w *whereBuilder, opts models.PokemonListOptions) {
+	if opts.Type != "" {
+		w.add("(primary_type = $?::text::pokemon_type OR secondary_type = $?::text::pokemon_type)", string(opts.Type), string(opts.Type))
+	}
+	if opts.MinPokedexID > 0 {
+		w.add("pokedex_id >= $?", opts.MinPokedexID)
+	}
+	if opts.MaxPokedexID > 0 {
+		w.add("pokedex_id <= $?", opts.MaxPokedexID)
+	}
+	if opts.NamePrefix != "" {
+		w.add("starts_with(LOWER(name), LOWER($?))", opts.NamePrefix)
+	}
+	if opts.CreatedAfter != nil {
+		w.add("created_at >= $?", *opts.CreatedAfter)
+	}
+	if opts.CreatedBefore != nil {
+		w.add("created_at < $?", *opts.CreatedBefore)
+	}
+	if opts.BoxID > 0 {
+		w.add("box_id = $?", opts.BoxID)
+	}
+	if opts.Tag != "" {
+		// Containment rather than ANY, so idx_pokemons_tags applies
+		w.add("tags @> ARRAY[$?::text]", opts.Tag)
+	}
+	if opts.Shiny != nil {
+		w.add("shiny = $?", *opts.Shiny)
+	}
+	if opts.Gender != "" {
+		w.add("gender = $?", string(opts.Gender))
+	}
+	if opts.Form != "" {
+		w.add("form = $?", opts.Form)
+	}
+	if opts.Ball != "" {
+		w.add("ball = $?", opts.Ball)
+	}
+	if opts.OriginalTrainer != "" {
+		w.add("LOWER(original_trainer) = LOWER($?)", opts.OriginalTrainer)
+	}
+	if opts.CaughtLocation != "" {
+		w.add("LOWER(caught_location) = LOWER($?)", opts.CaughtLocation)
+	}
+	if opts.CaughtAfter != nil {
+		w.add("caught_at >= $?", *opts.CaughtAfter)
+	}
+	if opts.CaughtBefore != nil {
+		w.add("caught_at < $?", *opts.CaughtBefore)
+	}
+}
+
+// pokemonSortValue picks the value of the sort column from a row
+func pokemonSortValue(field models.PokemonSortField, p *models.Pokemon) any {
+	switch field {
+	case models.SortByName:
+		return p.Name
+	case models.SortByPokedexID:
+		return p.PokedexID
+	case models.SortByWeight:
+		return p.Weight
+	case models.SortByHeight:
+		return p.Height
+	default:
+		return p.CreatedAt
+	}
+}
+
+// pokemonCursorValue decodes a cursor value back into the sort column's Go type.
+// The result is a pointer, which the driver dereferences.
+func pokemonCursorValue(field models.PokemonSortField, raw json.RawMessage) (any, error) {
+	var value any
+	switch field {
+	case models.SortByName:
+		value = new(string)
+	case models.SortByCreatedAt:
+		value = new(time.Time)
+	default:
+		value = new(int)
+	}
+	if err := json.Unmarshal(raw, value); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return value, nil
+}
+
+// GetPokemonByID fetches a single Pokemon, scoped to its owner.
+// Returns nil if it doesn't exist or belongs to someone else.
+func (r *postgresPokemonRepository) GetPokemonByID(ctx context.Context, userID, id int) (*models.Pokemon, error) {
+	query := `SELECT ` + pokemonColumns + ` FROM pokemons WHERE id = $1 AND user_id = $2`
+
+	var p models.Pokemon
+	err := scanPokemon(r.db.QueryRowContext(ctx, query, id, userID), &p)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	return &p, nil
+}
+
+// updatePokemonQuery is shared by UpdatePokemon and trades, which move Pokemon between owners
+const updatePokemonQuery = `
+	UPDATE pokemons
+	SET pokedex_id = $1, name = $2, nickname = $3,
+		primary_type = $4::text::pokemon_type, secondary_type = NULLIF($5, '')::pokemon_type,
+		height = $6, weight = $7,
+		level = $8, nature = $9, ability = $10,
+		iv_hp = $11, iv_attack = $12, iv_defense = $13, iv_sp_attack = $14, iv_sp_defense = $15, iv_speed = $16,
+		ev_hp = $17, ev_attack = $18, ev_defense = $19, ev_sp_attack = $20, ev_sp_defense = $21, ev_speed = $22,
+		held_item = $23, moves = COALESCE($24::text[], '{}'), friendship = $25,
+		tags = COALESCE($26::text[], '{}'),
+		shiny = $27, gender = $28, form = $29, ball = $30,
+		original_trainer = $31, caught_at = $32, caught_location = $33
+	WHERE id = $34 AND user_id = $35
+`
+
+func pokemonUpdateArgs(p *models.Pokemon) []any {
+	args := []any{
+		p.PokedexID, p.Name, p.Nickname, string(p.Types.Primary), string(p.Types.Secondary),
+		p.Height, p.Weight, p.Level, p.Nature, p.Ability,
+	}
+	args = append(args, statSpreadArgs(p.IVs)...)
+	args = append(args, statSpreadArgs(p.EVs)...)
+	args = append(args, p.HeldItem, p.Moves, p.Friendship, p.Tags)
+	args = append(args, p.Shiny, string(p.Gender), p.Form, p.Ball, p.OriginalTrainer, p.CaughtAt, p.CaughtLocation)
+	return append(args, p.ID, p.UserID)
+}
+
+// UpdatePokemon saves the editable fields, including the species after an evolution,
+// and records the events in the same transaction. Returns false if no row owned
+// by p.UserID matched.
+func (r *postgresPokemonRepository) UpdatePokemon(ctx context.Context, p *models.Pokemon, events ...models.PokemonEvent) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, updatePokemonQuery, pokemonUpdateArgs(p)...)
+	if err != nil {
+		return false, fmt.Errorf("update error: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if n != 1 {
+		return false, nil
+	}
+	if err := insertPokemonEvents(ctx, tx, events...); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// DeletePokemon releases a Pokemon, closing its history with a released event.
+// Returns false if no row owned by userID matched.
+func (r *postgresPokemonRepository) DeletePokemon(ctx context.Context, userID, id int) (bool, error) {
+	query := `
+		WITH released AS (
+			DELETE FROM pokemons WHERE id = $1 AND user_id = $2
+			RETURNING id, user_id, pokedex_id, name, nickname
+		)
+		INSERT INTO pokemon_events (pokemon_id, user_id, event_type, pokedex_id, name, nickname)
+		SELECT id, user_id, 'released', pokedex_id, name, nickname FROM released
+	`
+	res, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("delete error: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// ownsSpecies matches catalog rows that the user bound to param has caught at least
+// once. It probes idx_pokemons_user_pokedex_id, so it never scans the collection.
+func ownsSpecies(param string) string {
+	return `EXISTS (SELECT 1 FROM pokemons p WHERE p.user_id = ` + param + ` AND p.pokedex_id = species.id)`
+}
+
+// CountOwnedByGeneration counts, per generation, the catalog species and how many
+// of them the user has caught. Species without a generation come last, as ID 0.
+func (r *postgresPokemonRepository) CountOwnedByGeneration(ctx context.Context, userID int) ([]models.GenerationProgress, error) {
+	query := `
+		SELECT COALESCE(g.id, 0), COALESCE(g.name, ''), COALESCE(g.region, ''),
+			COUNT(*) FILTER (WHERE ` + ownsSpecies("$1") + `), COUNT(*)
+		FROM species
+		LEFT JOIN generations g ON g.id = species.generation_id
+		GROUP BY g.id, g.name, g.region
+		ORDER BY g.id NULLS LAST
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []models.GenerationProgress
+	for rows.Next() {
+		var c models.GenerationProgress
+		if err := rows.Scan(&c.ID, &c.Name, &c.Region, &c.Owned, &c.Total); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+// ListMissingSpecies returns one page of the species the user hasn't caught,
+// in Pokedex order. Total is left for the caller, which already has the counts.
+func (r *postgresPokemonRepository) ListMissingSpecies(ctx context.Context, opts models.DexProgressOptions) (*models.SpeciesPage, error) {
+	w := &whereBuilder{}
+	w.add("NOT "+ownsSpecies("$?"), opts.UserID)
+	if opts.GenerationID > 0 {
+		w.add("species.generation_id = $?", opts.GenerationID)
+	}
+
+	// Pokedex numbers are unique, so the cursor only needs the last id
+	if opts.Cursor != "" {
+		cursor, err := decodeCursor(opts.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if cursor.SortBy != "species" {
+			return nil, ErrInvalidCursor
+		}
+		w.add("species.id > $?", cursor.ID)
+	}
+
+	// Fetch one extra row to find out whether another page exists
+	query := fmt.Sprintf(
+		`SELECT %s FROM species %s ORDER BY species.id LIMIT %s`,
+		speciesColumns, w.sql(), w.placeholder(opts.Limit+1),
+	)
+	rows, err := r.db.QueryContext(ctx, query, w.args...)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	var species []models.Species
+	for rows.Next() {
+		var s models.Species
+		if err := scanSpecies(rows, &s); err != nil {
+			return nil, err
+		}
+		species = append(species, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	page := &models.SpeciesPage{Data: species}
+	if len(species) > opts.Limit {
+		page.Data = species[:opts.Limit]
+		page.NextCursor, err = encodeCursor(keysetCursor{SortBy: "species", ID: page.Data[len(page.Data)-1].ID})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
 }
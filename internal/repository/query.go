@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor can't be decoded
+// or was issued for a different sort order.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// whereBuilder collects SQL conditions and their arguments. Each "$?" in a
+// condition is replaced with the next positional placeholder ($1, $2, ...).
+type whereBuilder struct {
+	conds []string
+	args  []any
+}
+
+func (w *whereBuilder) add(cond string, args ...any) {
+	for _, arg := range args {
+		w.args = append(w.args, arg)
+		cond = strings.Replace(cond, "$?", fmt.Sprintf("$%d", len(w.args)), 1)
+	}
+	w.conds = append(w.conds, cond)
+}
+
+// placeholder registers an argument without a condition, e.g. for LIMIT.
+func (w *whereBuilder) placeholder(arg any) string {
+	w.args = append(w.args, arg)
+	return fmt.Sprintf("$%d", len(w.args))
+}
+
+func (w *whereBuilder) sql() string {
+	if len(w.conds) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(w.conds, " AND ")
+}
+
+// keysetCursor marks the last row of a page for keyset pagination.
+type keysetCursor struct {
+	SortBy     string          `json:"s"`
+	Descending bool            `json:"d"`
+	Value      json.RawMessage `json:"v"` // Sort column value of the last row
+	ID         int             `json:"id"`
+}
+
+func encodeCursor(c keysetCursor) (string, error) {
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeCursor(s string) (keysetCursor, error) {
+	var c keysetCursor
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return c, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// prefixedScanner lets a scan helper such as scanPokemon read rows that carry
+// extra leading columns, e.g. the team_id of a joined member row.
+type prefixedScanner struct {
+	row    rowScanner
+	prefix []any
+}
+
+func (s prefixedScanner) Scan(dest ...any) error {
+	return s.row.Scan(append(s.prefix, dest...)...)
+}
+
+// jsonColumn scans a JSON-encoded column, such as to_jsonb(text_array), into dst.
+type jsonColumn struct {
+	dst any
+}
+
+func (j jsonColumn) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, j.dst)
+	case string:
+		return json.Unmarshal([]byte(v), j.dst)
+	default:
+		return fmt.Errorf("cannot scan %T into a JSON column", src)
+	}
+}
+
+// nullTime passes a zero time as NULL, so the column default applies.
+func nullTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
+
+// statSpreadFields returns scan destinations for the six stats in column order.
+func statSpreadFields(s *models.StatSpread) []any {
+	return []any{&s.HP, &s.Attack, &s.Defense, &s.SpAttack, &s.SpDefense, &s.Speed}
+}
+
+// statSpreadArgs returns the six stats as query arguments in column order.
+func statSpreadArgs(s models.StatSpread) []any {
+	return []any{s.HP, s.Attack, s.Defense, s.SpAttack, s.SpDefense, s.Speed}
+}
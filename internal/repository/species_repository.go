@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+)
+
+type SpeciesRepository interface {
+	GetSpeciesByID(ctx context.Context, id int) (*models.Species, error)
+	GetSpeciesByIDs(ctx context.Context, ids []int) (map[int]models.Species, error)
+	GetSpeciesByName(ctx context.Context, name string) (*models.Species, error)
+	GetEvolutionsFrom(ctx context.Context, speciesID int) ([]models.Evolution, error)
+	GetEvolutionChain(ctx context.Context, chainID int) ([]models.Species, []models.Evolution, error)
+	UpsertGenerations(ctx context.Context, generations []models.Generation) error
+	UpsertSpecies(ctx context.Context, species []models.Species) error
+}
+
+type postgresSpeciesRepository struct {
+	db *sql.DB
+}
+
+func NewSpeciesRepository(db *sql.DB) SpeciesRepository {
+	return &postgresSpeciesRepository{db: db}
+}
+
+// speciesColumns must stay in sync with scanSpecies
+const speciesColumns = `id, name, primary_type::text, COALESCE(secondary_type::text, ''), height, weight,
+	base_hp, base_attack, base_defense, base_sp_attack, base_sp_defense, base_speed,
+	to_jsonb(abilities), evolves_from_id, COALESCE(chain_id, 0), COALESCE(generation_id, 0),
+	gender_rate, to_jsonb(forms)`
+
+func scanSpecies(row rowScanner, s *models.Species) error {
+	dest := []any{&s.ID, &s.Name, &s.Types.Primary, &s.Types.Secondary, &s.Height, &s.Weight}
+	dest = append(dest, statSpreadFields(&s.BaseStats)...)
+	dest = append(dest, jsonColumn{&s.Abilities}, &s.EvolvesFromID, &s.ChainID, &s.GenerationID)
+	return row.Scan(append(dest, &s.GenderRate, jsonColumn{&s.Forms})...)
+}
+
+// GetSpeciesByID returns nil if the catalog has no such Pokedex number
+func (r *postgresSpeciesRepository) GetSpeciesByID(ctx context.Context, id int) (*models.Species, error) {
+	query := `SELECT ` + speciesColumns + ` FROM species WHERE id = $1`
+
+	var s models.Species
+	err := scanSpecies(r.db.QueryRowContext(ctx, query, id), &s)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	return &s, nil
+}
+
+// GetSpeciesByName finds a species by its PokeAPI identifier, e.g. "mr-mime".
+// Returns nil if there is none.
+func (r *postgresSpeciesRepository) GetSpeciesByName(ctx context.Context, name string) (*models.Species, error) {
+	query := `SELECT ` + speciesColumns + ` FROM species WHERE name = $1`
+
+	var s models.Species
+	err := scanSpecies(r.db.QueryRowContext(ctx, query, name), &s)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	return &s, nil
+}
+
+// GetSpeciesByIDs looks up several species at once, keyed by Pokedex number.
+// Unknown numbers are simply missing from the map.
+func (r *postgresSpeciesRepository) GetSpeciesByIDs(ctx context.Context, ids []int) (map[int]models.Species, error) {
+	species := make(map[int]models.Species, len(ids))
+	if len(ids) == 0 {
+		return species, nil
+	}
+
+	query := `SELECT ` + speciesColumns + ` FROM species WHERE id = ANY($1)`
+	rows, err := r.db.QueryContext(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s models.Species
+		if err := scanSpecies(rows, &s); err != nil {
+			return nil, err
+		}
+		species[s.ID] = s
+	}
+	return species, rows.Err()
+}
+
+// evolutionColumns must stay in sync with scanEvolution
+const evolutionColumns = `from_species_id, to_species_id, trigger, min_level, min_friendship,
+	item, held_item, COALESCE(trade_species_id, 0), time_of_day, other_conditions`
+
+func scanEvolution(row rowScanner, e *models.Evolution) error {
+	return row.Scan(
+		&e.FromSpeciesID, &e.ToSpeciesID, &e.Trigger, &e.MinLevel, &e.MinFriendship,
+		&e.Item, &e.HeldItem, &e.TradeSpeciesID, &e.TimeOfDay, &e.OtherConditions,
+	)
+}
+
+func scanEvolutions(rows *sql.Rows) ([]models.Evolution, error) {
+	defer rows.Close()
+
+	var evolutions []models.Evolution
+	for rows.Next() {
+		var e models.Evolution
+		if err := scanEvolution(rows, &e); err != nil {
+			return nil, err
+		}
+		evolutions = append(evolutions, e)
+	}
+	return evolutions, rows.Err()
+}
+
+// GetEvolutionsFrom lists every way the species can evolve
+func (r *postgresSpeciesRepository) GetEvolutionsFrom(ctx context.Context, speciesID int) ([]models.Evolution, error) {
+	query := `SELECT ` + evolutionColumns + ` FROM species_evolutions WHERE from_species_id = $1 ORDER BY id`
+	rows, err := r.db.QueryContext(ctx, query, speciesID)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	return scanEvolutions(rows)
+}
+
+// GetEvolutionChain returns every species in the chain and the evolutions between them
+func (r *postgresSpeciesRepository) GetEvolutionChain(ctx context.Context, chainID int) ([]models.Species, []models.Evolution, error) {
+	query := `SELECT ` + speciesColumns + ` FROM species WHERE chain_id = $1 ORDER BY id`
+	rows, err := r.db.QueryContext(ctx, query, chainID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	var members []models.Species
+	for rows.Next() {
+		var s models.Species
+		if err := scanSpecies(rows, &s); err != nil {
+			return nil, nil, err
+		}
+		members = append(members, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	query = `
+		SELECT ` + evolutionColumns + `
+		FROM species_evolutions
+		WHERE from_species_id IN (SELECT id FROM species WHERE chain_id = $1)
+		ORDER BY id
+	`
+	evolutionRows, err := r.db.QueryContext(ctx, query, chainID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query error: %w", err)
+	}
+	evolutions, err := scanEvolutions(evolutionRows)
+	if err != nil {
+		return nil, nil, err
+	}
+	return members, evolutions, nil
+}
+
+// replaceEvolutions swaps the stored ways a species evolves for the imported ones
+func replaceEvolutions(ctx context.Context, tx *sql.Tx, s models.Species) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM species_evolutions WHERE from_species_id = $1`, s.ID); err != nil {
+		return fmt.Errorf("clearing evolutions of species %d: %w", s.ID, err)
+	}
+
+	query := `
+		INSERT INTO species_evolutions (
+			from_species_id, to_species_id, trigger, min_level, min_friendship,
+			item, held_item, trade_species_id, time_of_day, other_conditions
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, 0), $9, $10)
+	`
+	for _, e := range s.Evolutions {
+		_, err := tx.ExecContext(
+			ctx, query,
+			s.ID, e.ToSpeciesID, string(e.Trigger), e.MinLevel, e.MinFriendship,
+			e.Item, e.HeldItem, e.TradeSpeciesID, e.TimeOfDay, e.OtherConditions,
+		)
+		if err != nil {
+			return fmt.Errorf("inserting evolution %d -> %d: %w", s.ID, e.ToSpeciesID, err)
+		}
+	}
+	return nil
+}
+
+// UpsertGenerations inserts or refreshes generations. Run it before UpsertSpecies,
+// since species reference them.
+func (r *postgresSpeciesRepository) UpsertGenerations(ctx context.Context, generations []models.Generation) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO generations (id, name, region)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (id) DO UPDATE SET
+			name = EXCLUDED.name,
+			region = EXCLUDED.region
+	`
+	for _, g := range generations {
+		if _, err := tx.ExecContext(ctx, query, g.ID, g.Name, g.Region); err != nil {
+			return fmt.Errorf("upserting generation %d (%s): %w", g.ID, g.Name, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// UpsertSpecies inserts or refreshes catalog entries in a single transaction,
+// so a failed import never leaves a half-updated catalog.
+func (r *postgresSpeciesRepository) UpsertSpecies(ctx context.Context, species []models.Species) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO species (
+			id, name, primary_type, secondary_type, height, weight,
+			base_hp, base_attack, base_defense, base_sp_attack, base_sp_defense, base_speed,
+			abilities, evolves_from_id, chain_id, generation_id,
+			gender_rate, forms
+		)
+		VALUES (
+			$1, $2, $3::text::pokemon_type, NULLIF($4, '')::pokemon_type, $5, $6,
+			$7, $8, $9, $10, $11, $12,
+			COALESCE($13::text[], '{}'), $14, NULLIF($15, 0), NULLIF($16, 0),
+			$17, COALESCE($18::text[], '{}')
+		)
+		ON CONFLICT (id) DO UPDATE SET
+			name = EXCLUDED.name,
+			primary_type = EXCLUDED.primary_type,
+			secondary_type = EXCLUDED.secondary_type,
+			height = EXCLUDED.height,
+			weight = EXCLUDED.weight,
+			base_hp = EXCLUDED.base_hp,
+			base_attack = EXCLUDED.base_attack,
+			base_defense = EXCLUDED.base_defense,
+			base_sp_attack = EXCLUDED.base_sp_attack,
+			base_sp_defense = EXCLUDED.base_sp_defense,
+			base_speed = EXCLUDED.base_speed,
+			abilities = EXCLUDED.abilities,
+			evolves_from_id = EXCLUDED.evolves_from_id,
+			chain_id = EXCLUDED.chain_id,
+			generation_id = EXCLUDED.generation_id,
+			gender_rate = EXCLUDED.gender_rate,
+			forms = EXCLUDED.forms,
+			updated_at = CURRENT_TIMESTAMP
+	`
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("preparing upsert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, s := range species {
+		args := []any{s.ID, s.Name, string(s.Types.Primary), string(s.Types.Secondary), s.Height, s.Weight}
+		args = append(args, statSpreadArgs(s.BaseStats)...)
+		args = append(args, s.Abilities, s.EvolvesFromID, s.ChainID, s.GenerationID, s.GenderRate, s.Forms)
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			return fmt.Errorf("upserting species %d (%s): %w", s.ID, s.Name, err)
+		}
+		if err := replaceEvolutions(ctx, tx, s); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
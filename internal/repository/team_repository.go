@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+)
+
+// ErrPokemonNotOwned is returned when a referenced Pokemon doesn't exist or belongs to another user.
+var ErrPokemonNotOwned = errors.New("pokemon not owned by user")
+
+type TeamRepository interface {
+	CreateTeam(ctx context.Context, team *models.Team, pokemonIDs []int) error
+	GetTeam(ctx context.Context, userID, id int) (*models.Team, error)
+	ListTeams(ctx context.Context, userID int) ([]models.Team, error)
+	UpdateTeam(ctx context.Context, team *models.Team, pokemonIDs []int) (bool, error)
+	DeleteTeam(ctx context.Context, userID, id int) (bool, error)
+}
+
+type postgresTeamRepository struct {
+	db *sql.DB
+}
+
+func NewTeamRepository(db *sql.DB) TeamRepository {
+	return &postgresTeamRepository{db: db}
+}
+
+// CreateTeam inserts the team and its members in one transaction
+func (r *postgresTeamRepository) CreateTeam(ctx context.Context, team *models.Team, pokemonIDs []int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO teams (user_id, name)
+		VALUES ($1, $2)
+		RETURNING id, created_at, updated_at
+	`
+	if err := tx.QueryRowContext(ctx, query, team.UserID, team.Name).Scan(&team.ID, &team.CreatedAt, &team.UpdatedAt); err != nil {
+		return fmt.Errorf("insert error: %w", err)
+	}
+
+	if err := insertTeamMembers(ctx, tx, team.ID, team.UserID, pokemonIDs); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// insertTeamMembers fills slots 1..n. Each row is only inserted if the Pokemon
+// belongs to the team owner, so ownership is checked inside the transaction.
+func insertTeamMembers(ctx context.Context, tx *sql.Tx, teamID, userID int, pokemonIDs []int) error {
+	query := `
+		INSERT INTO team_members (team_id, pokemon_id, slot)
+		SELECT $1, id, $2 FROM pokemons WHERE id = $3 AND user_id = $4
+	`
+	for i, pokemonID := range pokemonIDs {
+		res, err := tx.ExecContext(ctx, query, teamID, i+1, pokemonID, userID)
+		if err != nil {
+			return fmt.Errorf("insert member error: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return fmt.Errorf("%w: pokemon %d", ErrPokemonNotOwned, pokemonID)
+		}
+	}
+	return nil
+}
+
+// GetTeam returns nil if the team doesn't exist or belongs to someone else
+func (r *postgresTeamRepository) GetTeam(ctx context.Context, userID, id int) (*models.Team, error) {
+	query := `SELECT id, user_id, name, created_at, updated_at FROM teams WHERE id = $1 AND user_id = $2`
+
+	var team models.Team
+	err := r.db.QueryRowContext(ctx, query, id, userID).Scan(
+		&team.ID, &team.UserID, &team.Name, &team.CreatedAt, &team.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+
+	teams := []models.Team{team}
+	if err := r.loadMembers(ctx, teams); err != nil {
+		return nil, err
+	}
+	return &teams[0], nil
+}
+
+func (r *postgresTeamRepository) ListTeams(ctx context.Context, userID int) ([]models.Team, error) {
+	query := `SELECT id, user_id, name, created_at, updated_at FROM teams WHERE user_id = $1 ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	var teams []models.Team
+	for rows.Next() {
+		var team models.Team
+		if err := rows.Scan(&team.ID, &team.UserID, &team.Name, &team.CreatedAt, &team.UpdatedAt); err != nil {
+			return nil, err
+		}
+		teams = append(teams, team)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadMembers(ctx, teams); err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
+
+// loadMembers fills Members for every team with a single query
+func (r *postgresTeamRepository) loadMembers(ctx context.Context, teams []models.Team) error {
+	if len(teams) == 0 {
+		return nil
+	}
+
+	byID := make(map[int]*models.Team, len(teams))
+	teamIDs := make([]int, len(teams))
+	for i := range teams {
+		teams[i].Members = []models.Pokemon{}
+		byID[teams[i].ID] = &teams[i]
+		teamIDs[i] = teams[i].ID
+	}
+
+	query := `
+		SELECT tm.team_id, ` + pokemonColumns + `
+		FROM team_members tm
+		JOIN pokemons ON pokemons.id = tm.pokemon_id
+		WHERE tm.team_id = ANY($1)
+		ORDER BY tm.team_id, tm.slot
+	`
+	rows, err := r.db.QueryContext(ctx, query, teamIDs)
+	if err != nil {
+		return fmt.Errorf("query members error: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var teamID int
+		var p models.Pokemon
+		if err := scanPokemon(prefixedScanner{rows, []any{&teamID}}, &p); err != nil {
+			return err
+		}
+		team := byID[teamID]
+		team.Members = append(team.Members, p)
+	}
+	return rows.Err()
+}
+
+// UpdateTeam renames the team and, when pokemonIDs is non-nil, replaces its members.
+// Returns false if no team owned by team.UserID matched.
+func (r *postgresTeamRepository) UpdateTeam(ctx context.Context, team *models.Team, pokemonIDs []int) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE teams SET name = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND user_id = $3
+		RETURNING updated_at
+	`
+	err = tx.QueryRowContext(ctx, query, team.Name, team.ID, team.UserID).Scan(&team.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("update error: %w", err)
+	}
+
+	if pokemonIDs != nil {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM team_members WHERE team_id = $1`, team.ID); err != nil {
+			return false, fmt.Errorf("clear members error: %w", err)
+		}
+		if err := insertTeamMembers(ctx, tx, team.ID, team.UserID, pokemonIDs); err != nil {
+			return false, err
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *postgresTeamRepository) DeleteTeam(ctx context.Context, userID, id int) (bool, error) {
+	query := `DELETE FROM teams WHERE id = $1 AND user_id = $2`
+	res, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("delete error: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
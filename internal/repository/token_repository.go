@@ -3,8 +3,8 @@ package repository
 import (
 	"context"
 	"database/sql"
-
-	// "time"
+	"fmt"
+	"time"
 
 	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
 )
@@ -13,6 +13,11 @@ type TokenRepository interface {
 	CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error
 	GetRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
 	RevokeRefreshToken(ctx context.Context, tokenHash string) error
+	RevokeAllRefreshTokens(ctx context.Context, userID int, audit ...models.AuditEntry) error
+	RotateRefreshToken(ctx context.Context, tokenHash string, successor *models.RefreshToken, grace time.Duration) (bool, error)
+	RevokeTokenFamily(ctx context.Context, familyID string) error
+	CreatePasswordResetToken(ctx context.Context, token *models.PasswordResetToken) error
+	ResetPassword(ctx context.Context, tokenHash, passwordHash string) (bool, error)
 }
 
 type postgresTokenRepository struct {
@@ -25,24 +30,25 @@ func NewTokenRepository(db *sql.DB) TokenRepository {
 
 func (r *postgresTokenRepository) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error {
 	query := `
-		INSERT INTO refresh_tokens (user_id, token_hash, expires_at)
-		VALUES ($1, $2, $3)
+		INSERT INTO refresh_tokens (user_id, token_hash, family_id, expires_at)
+		VALUES ($1, $2, $3, $4)
 		RETURNING id, created_at
 	`
 	return r.db.QueryRowContext(
-		ctx, query, token.UserID, token.TokenHash, token.ExpiresAt,
+		ctx, query, token.UserID, token.TokenHash, token.FamilyID, token.ExpiresAt,
 	).Scan(&token.ID, &token.CreatedAt)
 }
 
 func (r *postgresTokenRepository) GetRefreshToken(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
 	query := `
-		SELECT id, user_id, token_hash, expires_at, created_at 
-		FROM refresh_tokens 
+		SELECT id, user_id, token_hash, family_id, expires_at, rotated_at, created_at
+		FROM refresh_tokens
 		WHERE token_hash = $1
 	`
 	var token models.RefreshToken
 	err := r.db.QueryRowContext(ctx, query, tokenHash).Scan(
-		&token.ID, &token.UserID, &token.TokenHash, &token.ExpiresAt, &token.CreatedAt,
+		&token.ID, &token.UserID, &token.TokenHash, &token.FamilyID,
+		&token.ExpiresAt, &token.RotatedAt, &token.CreatedAt,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -58,3 +64,130 @@ func (r *postgresTokenRepository) RevokeRefreshToken(ctx context.Context, tokenH
 	_, err := r.db.ExecContext(ctx, query, tokenHash)
 	return err
 }
+
+// RevokeAllRefreshTokens removes every refresh token issued to the user,
+// signing them out of all devices. Audit entries are recorded in the same transaction.
+func (r *postgresTokenRepository) RevokeAllRefreshTokens(ctx context.Context, userID int, audit ...models.AuditEntry) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `DELETE FROM refresh_tokens WHERE user_id = $1`
+	if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+		return fmt.Errorf("revoke refresh tokens error: %w", err)
+	}
+	if err := insertAuditEntries(ctx, tx, audit...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RotateRefreshToken retires a token and stores its successor in one transaction,
+// so a failure in between can't leave a rotated token without a successor.
+// A token rotated less than grace ago may be exchanged again, which covers two
+// tabs refreshing at once and clients retrying a lost response. Returns false
+// if the token is gone or was rotated longer ago, which means it is being replayed.
+func (r *postgresTokenRepository) RotateRefreshToken(ctx context.Context, tokenHash string, successor *models.RefreshToken, grace time.Duration) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 1. Retire the old token, keeping the first rotation time. The row lock makes a
+	// concurrent refresh wait and then see the rotation.
+	query := `
+		UPDATE refresh_tokens SET rotated_at = COALESCE(rotated_at, NOW())
+		WHERE token_hash = $1 AND (rotated_at IS NULL OR rotated_at > NOW() - make_interval(secs => $2))
+	`
+	res, err := tx.ExecContext(ctx, query, tokenHash, grace.Seconds())
+	if err != nil {
+		return false, fmt.Errorf("rotate refresh token error: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if n != 1 {
+		return false, nil
+	}
+
+	// 2. Store the successor
+	query = `
+		INSERT INTO refresh_tokens (user_id, token_hash, family_id, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+	err = tx.QueryRowContext(
+		ctx, query, successor.UserID, successor.TokenHash, successor.FamilyID, successor.ExpiresAt,
+	).Scan(&successor.ID, &successor.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("insert refresh token error: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit error: %w", err)
+	}
+	return true, nil
+}
+
+// RevokeTokenFamily removes every token that descends from the same login.
+func (r *postgresTokenRepository) RevokeTokenFamily(ctx context.Context, familyID string) error {
+	query := `DELETE FROM refresh_tokens WHERE family_id = $1`
+	_, err := r.db.ExecContext(ctx, query, familyID)
+	return err
+}
+
+func (r *postgresTokenRepository) CreatePasswordResetToken(ctx context.Context, token *models.PasswordResetToken) error {
+	query := `
+		INSERT INTO password_reset_tokens (user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+	return r.db.QueryRowContext(ctx, query, token.UserID, token.TokenHash, token.ExpiresAt).Scan(&token.ID, &token.CreatedAt)
+}
+
+// ResetPassword spends the reset token and sets the new password in one transaction.
+// Every refresh token of the user is revoked and their other reset links stop working.
+// Returns false if the token is unknown, expired or already used.
+func (r *postgresTokenRepository) ResetPassword(ctx context.Context, tokenHash, passwordHash string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 1. Spend the token. The row lock makes a concurrent reset with the same token wait and then fail.
+	var userID int
+	query := `
+		UPDATE password_reset_tokens SET used_at = NOW()
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING user_id
+	`
+	err = tx.QueryRowContext(ctx, query, tokenHash).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("spend reset token error: %w", err)
+	}
+
+	// 2. Set the password
+	query = `UPDATE users SET password_hash = $1, updated_at = NOW() WHERE id = $2`
+	if _, err := tx.ExecContext(ctx, query, passwordHash, userID); err != nil {
+		return false, fmt.Errorf("update password error: %w", err)
+	}
+
+	// 3. Sign out everywhere and retire other reset links
+	if _, err := tx.ExecContext(ctx, `DELETE FROM refresh_tokens WHERE user_id = $1`, userID); err != nil {
+		return false, fmt.Errorf("revoke refresh tokens error: %w", err)
+	}
+	query = `UPDATE password_reset_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL`
+	if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+		return false, fmt.Errorf("retire reset tokens error: %w", err)
+	}
+
+	return true, tx.Commit()
+}
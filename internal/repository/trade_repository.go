@@ -0,0 +1,289 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+)
+
+// ErrTradeNotPending is returned when a trade was already accepted, declined or cancelled.
+var ErrTradeNotPending = errors.New("trade is no longer pending")
+
+// TradeEvolver may evolve a Pokemon on arrival. It edits pokemon in place;
+// partner is the Pokemon it was traded for.
+type TradeEvolver func(pokemon, partner *models.Pokemon) error
+
+type TradeRepository interface {
+	CreateTrade(ctx context.Context, trade *models.Trade) error
+	GetTrade(ctx context.Context, userID, id int) (*models.Trade, error)
+	ListTrades(ctx context.Context, userID int, status models.TradeStatus) ([]models.Trade, error)
+	AcceptTrade(ctx context.Context, recipientID, id int, evolve TradeEvolver) (bool, error)
+	DeclineTrade(ctx context.Context, recipientID, id int) (bool, error)
+	CancelTrade(ctx context.Context, proposerID, id int) (bool, error)
+}
+
+type postgresTradeRepository struct {
+	db *sql.DB
+}
+
+func NewTradeRepository(db *sql.DB) TradeRepository {
+	return &postgresTradeRepository{db: db}
+}
+
+// tradeColumns must stay in sync with scanTrade
+const tradeColumns = `id, proposer_id, recipient_id, offered_pokemon_id, requested_pokemon_id, status, created_at, resolved_at`
+
+func scanTrade(row rowScanner, t *models.Trade) error {
+	return row.Scan(
+		&t.ID, &t.ProposerID, &t.RecipientID, &t.OfferedPokemonID, &t.RequestedPokemonID,
+		&t.Status, &t.CreatedAt, &t.ResolvedAt,
+	)
+}
+
+// CreateTrade stores a pending trade. The service checks ownership beforehand;
+// it's checked again on accept, since Pokemon can change hands in between.
+func (r *postgresTradeRepository) CreateTrade(ctx context.Context, trade *models.Trade) error {
+	query := `
+		INSERT INTO trades (proposer_id, recipient_id, offered_pokemon_id, requested_pokemon_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, status, created_at
+	`
+	return r.db.QueryRowContext(
+		ctx, query,
+		trade.ProposerID, trade.RecipientID, trade.OfferedPokemonID, trade.RequestedPokemonID,
+	).Scan(&trade.ID, &trade.Status, &trade.CreatedAt)
+}
+
+// GetTrade returns nil unless userID is one of the two parties
+func (r *postgresTradeRepository) GetTrade(ctx context.Context, userID, id int) (*models.Trade, error) {
+	query := `SELECT ` + tradeColumns + ` FROM trades WHERE id = $1 AND (proposer_id = $2 OR recipient_id = $2)`
+
+	var t models.Trade
+	err := scanTrade(r.db.QueryRowContext(ctx, query, id, userID), &t)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+
+	if t.Status == models.TradeAccepted {
+		if t.Transfers, err = r.loadTransfers(ctx, t.ID); err != nil {
+			return nil, err
+		}
+	}
+	return &t, nil
+}
+
+func (r *postgresTradeRepository) loadTransfers(ctx context.Context, tradeID int) ([]models.TradeTransfer, error) {
+	query := `
+		SELECT pokemon_id, from_user_id, to_user_id, pokedex_id_before, pokedex_id_after, transferred_at
+		FROM trade_transfers WHERE trade_id = $1 ORDER BY id
+	`
+	rows, err := r.db.QueryContext(ctx, query, tradeID)
+	if err != nil {
+		return nil, fmt.Errorf("query transfers error: %w", err)
+	}
+	defer rows.Close()
+
+	var transfers []models.TradeTransfer
+	for rows.Next() {
+		var t models.TradeTransfer
+		if err := rows.Scan(&t.PokemonID, &t.FromUserID, &t.ToUserID, &t.PokedexIDBefore, &t.PokedexIDAfter, &t.TransferredAt); err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, t)
+	}
+	return transfers, rows.Err()
+}
+
+// ListTrades returns the trades the user proposed or received, newest first.
+// An empty status matches every trade.
+func (r *postgresTradeRepository) ListTrades(ctx context.Context, userID int, status models.TradeStatus) ([]models.Trade, error) {
+	w := &whereBuilder{}
+	w.add("(proposer_id = $? OR recipient_id = $?)", userID, userID)
+	if status != "" {
+		w.add("status = $?", string(status))
+	}
+
+	query := `SELECT ` + tradeColumns + ` FROM trades ` + w.sql() + ` ORDER BY id DESC`
+	rows, err := r.db.QueryContext(ctx, query, w.args...)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	var trades []models.Trade
+	for rows.Next() {
+		var t models.Trade
+		if err := scanTrade(rows, &t); err != nil {
+			return nil, err
+		}
+		trades = append(trades, t)
+	}
+	return trades, rows.Err()
+}
+
+// AcceptTrade swaps ownership in one transaction. The trade and both Pokemon are
+// locked first, so a concurrent release or trade can't interleave. Returns false if
+// recipientID didn't receive such a trade, and ErrTradeNotPending or ErrPokemonNotOwned
+// if the trade can no longer happen.
+func (r *postgresTradeRepository) AcceptTrade(ctx context.Context, recipientID, id int, evolve TradeEvolver) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 1. Lock the trade
+	var trade models.Trade
+	query := `SELECT ` + tradeColumns + ` FROM trades WHERE id = $1 AND recipient_id = $2 FOR UPDATE`
+	err = scanTrade(tx.QueryRowContext(ctx, query, id, recipientID), &trade)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("query error: %w", err)
+	}
+	if trade.Status != models.TradePending {
+		return false, ErrTradeNotPending
+	}
+
+	// 2. Lock both Pokemon in id order, so two trades over the same pair can't deadlock
+	query = `SELECT ` + pokemonColumns + ` FROM pokemons WHERE id = ANY($1) ORDER BY id FOR UPDATE`
+	rows, err := tx.QueryContext(ctx, query, []int{trade.OfferedPokemonID, trade.RequestedPokemonID})
+	if err != nil {
+		return false, fmt.Errorf("query pokemon error: %w", err)
+	}
+	locked := make(map[int]*models.Pokemon, 2)
+	for rows.Next() {
+		var p models.Pokemon
+		if err := scanPokemon(rows, &p); err != nil {
+			rows.Close()
+			return false, err
+		}
+		locked[p.ID] = &p
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+
+	// 3. Both sides must still own what they put up
+	offered, requested := locked[trade.OfferedPokemonID], locked[trade.RequestedPokemonID]
+	if offered == nil || offered.UserID != trade.ProposerID {
+		return false, fmt.Errorf("%w: pokemon %d", ErrPokemonNotOwned, trade.OfferedPokemonID)
+	}
+	if requested == nil || requested.UserID != trade.RecipientID {
+		return false, fmt.Errorf("%w: pokemon %d", ErrPokemonNotOwned, trade.RequestedPokemonID)
+	}
+
+	// 4. Hand each Pokemon over, evolving it on arrival if its species does
+	handovers := []struct {
+		pokemon, partner *models.Pokemon
+		to               int
+	}{
+		{offered, requested, trade.RecipientID},
+		{requested, offered, trade.ProposerID},
+	}
+	// Both evolve against the partner as it was before the trade
+	before := map[int]models.Pokemon{offered.ID: *offered, requested.ID: *requested}
+	for _, m := range handovers {
+		partner := before[m.partner.ID]
+		if err := evolve(m.pokemon, &partner); err != nil {
+			return false, err
+		}
+	}
+
+	for _, m := range handovers {
+		from := m.pokemon.UserID
+		// Boxes and tags belong to the old owner's organization, so they don't travel
+		query = `UPDATE pokemons SET user_id = $1, box_id = NULL, box_slot = NULL WHERE id = $2`
+		if _, err := tx.ExecContext(ctx, query, m.to, m.pokemon.ID); err != nil {
+			return false, fmt.Errorf("transfer error: %w", err)
+		}
+		m.pokemon.UserID = m.to
+		m.pokemon.BoxID, m.pokemon.BoxSlot = nil, 0
+		m.pokemon.Tags = []string{}
+		if _, err := tx.ExecContext(ctx, updatePokemonQuery, pokemonUpdateArgs(m.pokemon)...); err != nil {
+			return false, fmt.Errorf("update error: %w", err)
+		}
+
+		query = `
+			INSERT INTO trade_transfers (trade_id, pokemon_id, from_user_id, to_user_id, pokedex_id_before, pokedex_id_after)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`
+		_, err := tx.ExecContext(ctx, query, trade.ID, m.pokemon.ID, from, m.to, before[m.pokemon.ID].PokedexID, m.pokemon.PokedexID)
+		if err != nil {
+			return false, fmt.Errorf("record transfer error: %w", err)
+		}
+
+		// The trade happens to the Pokemon as it was; an evolution follows on arrival
+		arrived := before[m.pokemon.ID]
+		arrived.UserID = m.to
+		traded := models.NewPokemonEvent(&arrived, models.EventTraded, models.PokemonEventDetails{TradeID: trade.ID})
+		traded.RelatedUserID = &from
+		events := []models.PokemonEvent{traded}
+		if m.pokemon.PokedexID != arrived.PokedexID {
+			events = append(events, models.NewPokemonEvent(m.pokemon, models.EventEvolved, models.PokemonEventDetails{
+				FromPokedexID: arrived.PokedexID,
+				FromName:      arrived.Name,
+				TradeID:       trade.ID,
+			}))
+		}
+		if err := insertPokemonEvents(ctx, tx, events...); err != nil {
+			return false, err
+		}
+	}
+
+	// 5. Traded Pokemon leave their old owner's teams
+	pokemonIDs := []int{offered.ID, requested.ID}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM team_members WHERE pokemon_id = ANY($1)`, pokemonIDs); err != nil {
+		return false, fmt.Errorf("clear team members error: %w", err)
+	}
+
+	// 6. Close this trade and every other pending offer for either Pokemon
+	query = `UPDATE trades SET status = $1, resolved_at = CURRENT_TIMESTAMP WHERE id = $2`
+	if _, err := tx.ExecContext(ctx, query, string(models.TradeAccepted), trade.ID); err != nil {
+		return false, fmt.Errorf("update trade error: %w", err)
+	}
+	query = `
+		UPDATE trades SET status = $1, resolved_at = CURRENT_TIMESTAMP
+		WHERE status = $2 AND (offered_pokemon_id = ANY($3) OR requested_pokemon_id = ANY($3))
+	`
+	if _, err := tx.ExecContext(ctx, query, string(models.TradeCancelled), string(models.TradePending), pokemonIDs); err != nil {
+		return false, fmt.Errorf("cancel stale trades error: %w", err)
+	}
+
+	return true, tx.Commit()
+}
+
+// DeclineTrade lets the recipient turn down a pending trade. Returns false if nothing matched.
+func (r *postgresTradeRepository) DeclineTrade(ctx context.Context, recipientID, id int) (bool, error) {
+	return r.resolve(ctx, "recipient_id", recipientID, id, models.TradeDeclined)
+}
+
+// CancelTrade lets the proposer withdraw a pending trade. Returns false if nothing matched.
+func (r *postgresTradeRepository) CancelTrade(ctx context.Context, proposerID, id int) (bool, error) {
+	return r.resolve(ctx, "proposer_id", proposerID, id, models.TradeCancelled)
+}
+
+// resolve closes a pending trade on behalf of one party. userColumn is a constant from this file.
+func (r *postgresTradeRepository) resolve(ctx context.Context, userColumn string, userID, id int, status models.TradeStatus) (bool, error) {
+	query := fmt.Sprintf(`
+		UPDATE trades SET status = $1, resolved_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND %s = $3 AND status = $4
+	`, userColumn)
+	res, err := r.db.ExecContext(ctx, query, string(status), id, userID, string(models.TradePending))
+	if err != nil {
+		return false, fmt.Errorf("update error: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
@@ -12,6 +12,12 @@ import (
 type UserRepository interface {
 	CreateUser(ctx context.Context, user *models.User) error
 	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
+	GetUserByID(ctx context.Context, id int) (*models.User, error)
+	MarkEmailVerified(ctx context.Context, id int, email string) (bool, error)
+	ReserveVerificationEmail(ctx context.Context, id int, interval time.Duration) (bool, error)
+	ReservePasswordResetEmail(ctx context.Context, id int, interval time.Duration) (bool, error)
+	ListUsers(ctx context.Context, opts models.UserListOptions) (*models.UserPage, error)
+	SetUserDisabled(ctx context.Context, id int, disabled bool, audit ...models.AuditEntry) (bool, error)
 }
 
 type postgresUserRepository struct {
@@ -24,15 +30,24 @@ func NewUserRepository(db *sql.DB) UserRepository {
 	}
 }
 
+// userColumns must stay in sync with scanUser
+const userColumns = `id, email, password_hash, role, disabled_at, verified_at, verification_sent_at,
+	COALESCE(totp_secret, ''), totp_enabled_at`
+
+func scanUser(row rowScanner, u *models.User) error {
+	return row.Scan(&u.ID, &u.Email, &u.Password, &u.Role, &u.DisabledAt, &u.VerifiedAt, &u.VerificationSentAt,
+		&u.TOTPSecret, &u.TOTPEnabledAt)
+}
+
 // CreateUser inserts a new user into the database
 func (r *postgresUserRepository) CreateUser(ctx context.Context, user *models.User) error {
 	query := `
 		INSERT INTO users (email, password_hash, created_at, updated_at)
 		VALUES ($1, $2, $3, $4)
-		RETURNING id
+		RETURNING id, role
 	`
 
-	// QueryRowContext is used because we expect 1 row back (the ID)
+	// QueryRowContext is used because we expect 1 row back (the ID and default role)
 	err := r.db.QueryRowContext(
 		ctx,
 		query,
@@ -40,7 +55,7 @@ func (r *postgresUserRepository) CreateUser(ctx context.Context, user *models.Us
 		user.Password, // Note: This should be the HASHED password
 		time.Now(),
 		time.Now(),
-	).Scan(&user.ID)
+	).Scan(&user.ID, &user.Role)
 
 	if err != nil {
 		return fmt.Errorf("failed to insert user: %w", err)
@@ -51,10 +66,27 @@ func (r *postgresUserRepository) CreateUser(ctx context.Context, user *models.Us
 
 // GetUserByEmail fetches a user by their email address
 func (r *postgresUserRepository) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
-	query := `SELECT id, email, password_hash FROM users WHERE email = $1`
+	query := `SELECT ` + userColumns + ` FROM users WHERE email = $1`
+
+	var user models.User
+	err := scanUser(r.db.QueryRowContext(ctx, query, email), &user)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("repository error: %w", err)
+	}
+
+	return &user, nil
+}
+
+// GetUserByID fetches a user by their primary key
+func (r *postgresUserRepository) GetUserByID(ctx context.Context, id int) (*models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
 
 	var user models.User
-	err := r.db.QueryRowContext(ctx, query, email).Scan(&user.ID, &user.Email, &user.Password)
+	err := scanUser(r.db.QueryRowContext(ctx, query, id), &user)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -65,3 +97,163 @@ func (r *postgresUserRepository) GetUserByEmail(ctx context.Context, email strin
 
 	return &user, nil
 }
+
+// MarkEmailVerified confirms the address, keeping the first confirmation time.
+// Returns false if the user no longer has that email.
+func (r *postgresUserRepository) MarkEmailVerified(ctx context.Context, id int, email string) (bool, error) {
+	query := `UPDATE users SET verified_at = COALESCE(verified_at, NOW()) WHERE id = $1 AND email = $2`
+	res, err := r.db.ExecContext(ctx, query, id, email)
+	if err != nil {
+		return false, fmt.Errorf("update error: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// ReserveVerificationEmail records that a verification email is about to be sent.
+// It returns false when the user is already verified or was sent one less than
+// interval ago, so concurrent resends can't both go out.
+func (r *postgresUserRepository) ReserveVerificationEmail(ctx context.Context, id int, interval time.Duration) (bool, error) {
+	query := `
+		UPDATE users SET verification_sent_at = NOW()
+		WHERE id = $1 AND verified_at IS NULL
+			AND (verification_sent_at IS NULL OR verification_sent_at <= NOW() - make_interval(secs => $2))
+	`
+	res, err := r.db.ExecContext(ctx, query, id, interval.Seconds())
+	if err != nil {
+		return false, fmt.Errorf("update error: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// ReservePasswordResetEmail records that a reset email is about to be sent. It
+// returns false when the account is disabled or was sent one less than interval ago.
+func (r *postgresUserRepository) ReservePasswordResetEmail(ctx context.Context, id int, interval time.Duration) (bool, error) {
+	query := `
+		UPDATE users SET password_reset_sent_at = NOW()
+		WHERE id = $1 AND disabled_at IS NULL
+			AND (password_reset_sent_at IS NULL OR password_reset_sent_at <= NOW() - make_interval(secs => $2))
+	`
+	res, err := r.db.ExecContext(ctx, query, id, interval.Seconds())
+	if err != nil {
+		return false, fmt.Errorf("update error: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// ListUsers pages through accounts in id order, filtered by opts.
+func (r *postgresUserRepository) ListUsers(ctx context.Context, opts models.UserListOptions) (*models.UserPage, error) {
+	w := &whereBuilder{}
+	if opts.Email != "" {
+		w.add("strpos(LOWER(email), LOWER($?)) > 0", opts.Email)
+	}
+	if opts.Role != "" {
+		w.add("role = $?", string(opts.Role))
+	}
+	if opts.Disabled != nil {
+		if *opts.Disabled {
+			w.add("disabled_at IS NOT NULL")
+		} else {
+			w.add("disabled_at IS NULL")
+		}
+	}
+	if opts.Cursor != "" {
+		cursor, err := decodeCursor(opts.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if cursor.SortBy != "users" {
+			return nil, ErrInvalidCursor
+		}
+		w.add("id > $?", cursor.ID)
+	}
+
+	// Fetch one extra row to find out whether another page exists
+	query := fmt.Sprintf(
+		`SELECT %s FROM users %s ORDER BY id LIMIT %s`,
+		userColumns, w.sql(), w.placeholder(opts.Limit+1),
+	)
+	rows, err := r.db.QueryContext(ctx, query, w.args...)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		var u models.User
+		if err := scanUser(rows, &u); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	page := &models.UserPage{Data: users}
+	if len(users) > opts.Limit {
+		page.Data = users[:opts.Limit]
+		page.NextCursor, err = encodeCursor(keysetCursor{SortBy: "users", ID: page.Data[len(page.Data)-1].ID})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+// SetUserDisabled switches an account off or back on. Disabling keeps the first
+// disabled_at and revokes every refresh token; that and the audit entries are
+// written in the same transaction. Returns false if the user doesn't exist.
+func (r *postgresUserRepository) SetUserDisabled(ctx context.Context, id int, disabled bool, audit ...models.AuditEntry) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// 1. Flip the flag
+	query := `UPDATE users SET disabled_at = NULL, updated_at = NOW() WHERE id = $1`
+	if disabled {
+		query = `UPDATE users SET disabled_at = COALESCE(disabled_at, NOW()), updated_at = NOW() WHERE id = $1`
+	}
+	res, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		return false, fmt.Errorf("update error: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if n != 1 {
+		return false, nil
+	}
+
+	// 2. A disabled user can't refresh their way back in
+	if disabled {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM refresh_tokens WHERE user_id = $1`, id); err != nil {
+			return false, fmt.Errorf("revoke refresh tokens error: %w", err)
+		}
+	}
+
+	// 3. Record who did it
+	if err := insertAuditEntries(ctx, tx, audit...); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit error: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,219 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/service"
+)
+
+// actorFromContext reads the caller that AuthMiddleware and RefreshRole put in the context.
+func actorFromContext(c *gin.Context) (service.Actor, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		return service.Actor{}, false
+	}
+	role, _ := c.Get("role")
+	r, _ := role.(models.Role)
+	return service.Actor{UserID: userID.(int), Role: r}, true
+}
+
+// respondAdminError answers the errors every account action shares, and reports whether err was one of them.
+func respondAdminError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	case errors.Is(err, service.ErrInsufficientRole):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions for this user"})
+	default:
+		return false
+	}
+	return true
+}
+
+// ListUsersRequest holds the query string of the admin user list
+type ListUsersRequest struct {
+	Email    string `form:"email"` // Matches anywhere in the address
+	Role     string `form:"role"`
+	Disabled *bool  `form:"disabled"`
+	Limit    int    `form:"limit"`
+	Cursor   string `form:"cursor"`
+}
+
+func (s *Server) adminListUsersHandler(c *gin.Context) {
+	log := s.logger.With("handler", "adminListUsers")
+
+	actor, exists := actorFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req ListUsersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := s.adminService.ListUsers(c.Request.Context(), actor, models.UserListOptions{
+		Email:    req.Email,
+		Role:     models.Role(req.Role),
+		Disabled: req.Disabled,
+		Limit:    req.Limit,
+		Cursor:   req.Cursor,
+	})
+	if err != nil {
+		if respondAdminError(c, err) {
+			return
+		}
+		log.Error("Failed to list users", "actor_id", actor.UserID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func (s *Server) adminDisableUserHandler(c *gin.Context) {
+	s.adminSetDisabled(c, true)
+}
+
+func (s *Server) adminEnableUserHandler(c *gin.Context) {
+	s.adminSetDisabled(c, false)
+}
+
+func (s *Server) adminSetDisabled(c *gin.Context, disabled bool) {
+	log := s.logger.With("handler", "adminSetDisabled")
+
+	actor, exists := actorFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	id, ok := idParam(c, "user")
+	if !ok {
+		return
+	}
+
+	var err error
+	if disabled {
+		err = s.adminService.DisableUser(c.Request.Context(), actor, id)
+	} else {
+		err = s.adminService.EnableUser(c.Request.Context(), actor, id)
+	}
+	if err != nil {
+		if respondAdminError(c, err) {
+			return
+		}
+		log.Error("Failed to update user status", "actor_id", actor.UserID, "user_id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+
+	log.Info("User status changed", "actor_id", actor.UserID, "user_id", id, "disabled", disabled)
+	message := "User enabled"
+	if disabled {
+		message = "User disabled and signed out"
+	}
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+func (s *Server) adminForceLogoutHandler(c *gin.Context) {
+	log := s.logger.With("handler", "adminForceLogout")
+
+	actor, exists := actorFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	id, ok := idParam(c, "user")
+	if !ok {
+		return
+	}
+
+	if err := s.adminService.ForceLogout(c.Request.Context(), actor, id); err != nil {
+		if respondAdminError(c, err) {
+			return
+		}
+		log.Error("Failed to force logout", "actor_id", actor.UserID, "user_id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+
+	log.Info("User forced out of all sessions", "actor_id", actor.UserID, "user_id", id)
+	c.JSON(http.StatusOK, gin.H{"message": "User logged out of all sessions"})
+}
+
+func (s *Server) adminUserCollectionHandler(c *gin.Context) {
+	log := s.logger.With("handler", "adminUserCollection")
+
+	actor, exists := actorFromContext(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	id, ok := idParam(c, "user")
+	if !ok {
+		return
+	}
+
+	var req ListPokemonRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := s.adminService.UserCollection(c.Request.Context(), actor, id, req.options())
+	if err != nil {
+		if respondAdminError(c, err) {
+			return
+		}
+		log.Error("Failed to list user's pokemon", "actor_id", actor.UserID, "user_id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pokemon"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// AuditLogRequest holds the query string of the audit log
+type AuditLogRequest struct {
+	Actor  int    `form:"actor_id"`
+	Target int    `form:"target_user_id"`
+	Action string `form:"action"`
+	Limit  int    `form:"limit"`
+	Cursor string `form:"cursor"`
+}
+
+func (s *Server) adminAuditLogHandler(c *gin.Context) {
+	log := s.logger.With("handler", "adminAuditLog")
+
+	var req AuditLogRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := s.adminService.AuditLog(c.Request.Context(), models.AuditLogOptions{
+		ActorID:      req.Actor,
+		TargetUserID: req.Target,
+		Action:       models.AuditAction(req.Action),
+		Limit:        req.Limit,
+		Cursor:       req.Cursor,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Error("Failed to list audit log", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audit log"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
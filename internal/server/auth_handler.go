@@ -1,11 +1,31 @@
 package server
 
 import (
+	"context"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/service"
 )
 
+const refreshCookieName = "refresh_token"
+
+// setRefreshCookie stores the refresh token in an HttpOnly cookie.
+func (s *Server) setRefreshCookie(c *gin.Context, refreshToken string) {
+	// FIXED: Make "Secure" dynamic based on environment
+	isProd := false
+	c.SetCookie(refreshCookieName, refreshToken, 7*24*3600, "/", "", isProd, true)
+}
+
+// clearRefreshCookie tells the browser to drop the refresh token cookie.
+func (s *Server) clearRefreshCookie(c *gin.Context) {
+	isProd := false
+	c.SetCookie(refreshCookieName, "", -1, "/", "", isProd, true)
+}
+
 type RegisterRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required,min=8"`
@@ -30,12 +50,25 @@ func (s *Server) registerHandler(c *gin.Context) {
 
 	log.Info("User registered successfully", "email", user.Email, "user_id", user.ID)
 
+	// The account exists either way; a failed email can be sent again through resend
+	message := "User registered successfully, check your email to verify your address"
+	if err := s.authService.SendVerificationEmail(c.Request.Context(), user.ID); err != nil {
+		log.Error("Failed to send verification email", "user_id", user.ID, "error", err)
+		message = "User registered successfully, but the verification email could not be sent"
+	}
+
 	c.JSON(http.StatusCreated, gin.H{
-		"message": "User registered successfully",
+		"message": message,
 		"user":    user,
 	})
 }
 
+// respondThrottled answers 429 with the wait in whole seconds
+func respondThrottled(c *gin.Context, err *service.ThrottledError) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(err.RetryAfter.Seconds()))))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+}
+
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
@@ -51,20 +84,40 @@ func (s *Server) loginHandler(c *gin.Context) {
 		return
 	}
 
-	accessToken, refreshToken, err := s.authService.Login(c.Request.Context(), req.Email, req.Password)
+	result, err := s.authService.Login(c.Request.Context(), req.Email, req.Password, c.ClientIP())
+	var throttled *service.ThrottledError
+	if errors.As(err, &throttled) {
+		log.Warn("Login throttled", "email", req.Email, "client_ip", c.ClientIP(), "retry_after", throttled.RetryAfter)
+		respondThrottled(c, throttled)
+		return
+	}
+	if errors.Is(err, service.ErrUserDisabled) {
+		log.Warn("Login attempt on disabled account", "email", req.Email)
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account disabled"})
+		return
+	}
 	if err != nil {
 		log.Warn("Login failed", "email", req.Email, "error", err)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
 
-	// 3. FIXED: Make "Secure" dynamic based on environment
-	isProd := false
-	c.SetCookie("refresh_token", refreshToken, 7*24*3600, "/", "", isProd, true)
+	// No session yet: the client sends a code along with this token to /auth/mfa/verify
+	if result.MFARequired() {
+		log.Info("Password accepted, awaiting second factor", "email", req.Email)
+		c.JSON(http.StatusOK, gin.H{
+			"mfa_required": true,
+			"mfa_token":    result.MFAToken,
+			"message":      "Enter the code from your authenticator app",
+		})
+		return
+	}
+
+	s.setRefreshCookie(c, result.RefreshToken)
 
 	log.Info("User logged in", "email", req.Email)
 	c.JSON(http.StatusOK, gin.H{
-		"access_token": accessToken,
+		"access_token": result.AccessToken,
 		"message":      "Login successful",
 	})
 }
@@ -72,20 +125,32 @@ func (s *Server) loginHandler(c *gin.Context) {
 func (s *Server) refreshHandler(c *gin.Context) {
 	log := s.logger.With("handler", "refresh")
 
-	cookie, err := c.Cookie("refresh_token")
+	cookie, err := c.Cookie(refreshCookieName)
 	if err != nil {
 		log.Warn("Refresh token missing") // <--- Log it
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token missing"})
 		return
 	}
 
-	newAccessToken, err := s.authService.Refresh(c.Request.Context(), cookie)
+	newAccessToken, newRefreshToken, err := s.authService.Refresh(c.Request.Context(), cookie)
 	if err != nil {
-		log.Warn("Refresh failed", "error", err) // <--- Log security events
+		if errors.Is(err, service.ErrRefreshTokenReused) {
+			// A rotated token came back: someone has a stolen copy. The service already revoked the family.
+			log.Error("Security incident: refresh token reuse detected", "client_ip", c.ClientIP(), "error", err)
+		} else {
+			log.Warn("Refresh failed", "error", err) // <--- Log security events
+		}
+		s.clearRefreshCookie(c)
+		if errors.Is(err, service.ErrUserDisabled) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Account disabled"})
+			return
+		}
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
 		return
 	}
 
+	s.setRefreshCookie(c, newRefreshToken)
+
 	log.Info("Token refreshed successfully") // <--- Success log
 
 	c.JSON(http.StatusOK, gin.H{
@@ -93,3 +158,170 @@ func (s *Server) refreshHandler(c *gin.Context) {
 		"message":      "Token refreshed successfully",
 	})
 }
+
+func (s *Server) logoutHandler(c *gin.Context) {
+	log := s.logger.With("handler", "logout")
+
+	cookie, err := c.Cookie(refreshCookieName)
+	if err == nil {
+		if err := s.authService.Logout(c.Request.Context(), cookie); err != nil {
+			log.Error("Logout failed", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+			return
+		}
+	}
+
+	// Clear the cookie even if it was missing, so the client ends up in a clean state
+	s.clearRefreshCookie(c)
+
+	log.Info("User logged out")
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+func (s *Server) logoutAllHandler(c *gin.Context) {
+	log := s.logger.With("handler", "logoutAll")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := s.authService.LogoutAll(c.Request.Context(), userID.(int)); err != nil {
+		log.Error("Logout from all sessions failed", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+
+	s.clearRefreshCookie(c)
+
+	log.Info("User logged out of all sessions", "user_id", userID)
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out of all sessions"})
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+func (s *Server) forgotPasswordHandler(c *gin.Context) {
+	log := s.logger.With("handler", "forgotPassword")
+
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warn("Invalid forgot password payload", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	// Sent in the background, so neither failures nor the mail server's latency
+	// tell the caller whether the account exists
+	s.runInBackground(func(ctx context.Context) {
+		if err := s.authService.ForgotPassword(ctx, req.Email); err != nil {
+			log.Error("Failed to send reset link", "email", req.Email, "error", err)
+		}
+	})
+
+	// Same answer whether or not the account exists
+	c.JSON(http.StatusAccepted, gin.H{"message": "If the account exists, a reset link has been sent"})
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+func (s *Server) resetPasswordHandler(c *gin.Context) {
+	log := s.logger.With("handler", "resetPassword")
+
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warn("Invalid reset password payload", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	err := s.authService.ResetPassword(c.Request.Context(), req.Token, req.Password)
+	if errors.Is(err, service.ErrInvalidResetToken) {
+		log.Warn("Password reset with an invalid token", "client_ip", c.ClientIP())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired reset token"})
+		return
+	}
+	if err != nil {
+		log.Error("Password reset failed", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+
+	// Every refresh token was revoked, including the one in this browser
+	s.clearRefreshCookie(c)
+
+	log.Info("Password reset")
+	c.JSON(http.StatusOK, gin.H{"message": "Password has been reset, please log in again"})
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+func (s *Server) verifyEmailHandler(c *gin.Context) {
+	log := s.logger.With("handler", "verifyEmail")
+
+	var req VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warn("Invalid verify payload", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	err := s.authService.VerifyEmail(c.Request.Context(), req.Token)
+	if errors.Is(err, service.ErrInvalidVerificationToken) {
+		log.Warn("Email verification with an invalid token", "client_ip", c.ClientIP())
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired verification token"})
+		return
+	}
+	if err != nil {
+		log.Error("Email verification failed", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+
+	log.Info("Email verified")
+	c.JSON(http.StatusOK, gin.H{"message": "Email verified, refresh your access token to pick it up"})
+}
+
+func (s *Server) resendVerificationHandler(c *gin.Context) {
+	log := s.logger.With("handler", "resendVerification")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	err := s.authService.SendVerificationEmail(c.Request.Context(), userID.(int))
+	var throttled *service.ThrottledError
+	switch {
+	case errors.As(err, &throttled):
+		respondThrottled(c, throttled)
+		return
+	case errors.Is(err, service.ErrAlreadyVerified):
+		c.JSON(http.StatusConflict, gin.H{"error": "Email already verified"})
+		return
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	case err != nil:
+		log.Error("Failed to resend verification email", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+
+	log.Info("Verification email resent", "user_id", userID)
+	c.JSON(http.StatusAccepted, gin.H{"message": "Verification email sent"})
+}
+
+func (s *Server) jwksHandler(c *gin.Context) {
+	// Keys change rarely, but short caching keeps rotations visible quickly
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, s.jwt.JWKS())
+}
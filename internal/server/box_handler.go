@@ -0,0 +1,223 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/service"
+)
+
+type BoxRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// MovePokemonRequest places a Pokemon; omit box_id to take it out of its box
+// and omit slot to use the first free one.
+type MovePokemonRequest struct {
+	BoxID int `json:"box_id"`
+	Slot  int `json:"slot"`
+}
+
+type SwapPokemonRequest struct {
+	PokemonID int `json:"pokemon_id" binding:"required"`
+}
+
+// respondBoxError maps service errors to status codes
+func (s *Server) respondBoxError(c *gin.Context, action string, err error) {
+	switch {
+	case errors.Is(err, service.ErrBoxNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Box not found"})
+	case errors.Is(err, service.ErrPokemonNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pokemon not found"})
+	case errors.Is(err, service.ErrBoxSlotConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		s.logger.Error("Failed to "+action, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+	}
+}
+
+func (s *Server) createBoxHandler(c *gin.Context) {
+	log := s.logger.With("handler", "createBox")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req BoxRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warn("Invalid JSON body", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	box, err := s.boxService.Create(c.Request.Context(), userID.(int), req.Name)
+	if err != nil {
+		s.respondBoxError(c, "create box", err)
+		return
+	}
+
+	log.Info("Box created", "user_id", userID, "box_id", box.ID)
+	c.JSON(http.StatusCreated, box)
+}
+
+func (s *Server) listBoxesHandler(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	boxes, err := s.boxService.List(c.Request.Context(), userID.(int))
+	if err != nil {
+		s.respondBoxError(c, "list boxes", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": boxes})
+}
+
+func (s *Server) getBoxHandler(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, ok := idParam(c, "box")
+	if !ok {
+		return
+	}
+
+	box, err := s.boxService.Get(c.Request.Context(), userID.(int), id)
+	if err != nil {
+		s.respondBoxError(c, "fetch box", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, box)
+}
+
+func (s *Server) renameBoxHandler(c *gin.Context) {
+	log := s.logger.With("handler", "renameBox")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, ok := idParam(c, "box")
+	if !ok {
+		return
+	}
+
+	var req BoxRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warn("Invalid JSON body", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	box, err := s.boxService.Rename(c.Request.Context(), userID.(int), id, req.Name)
+	if err != nil {
+		s.respondBoxError(c, "rename box", err)
+		return
+	}
+
+	log.Info("Box renamed", "user_id", userID, "box_id", box.ID)
+	c.JSON(http.StatusOK, box)
+}
+
+func (s *Server) deleteBoxHandler(c *gin.Context) {
+	log := s.logger.With("handler", "deleteBox")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, ok := idParam(c, "box")
+	if !ok {
+		return
+	}
+
+	if err := s.boxService.Delete(c.Request.Context(), userID.(int), id); err != nil {
+		s.respondBoxError(c, "delete box", err)
+		return
+	}
+
+	log.Info("Box deleted", "user_id", userID, "box_id", id)
+	c.Status(http.StatusNoContent)
+}
+
+func (s *Server) movePokemonHandler(c *gin.Context) {
+	log := s.logger.With("handler", "movePokemon")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, ok := idParam(c, "pokemon")
+	if !ok {
+		return
+	}
+
+	var req MovePokemonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warn("Invalid JSON body", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pokemon, err := s.boxService.Move(c.Request.Context(), userID.(int), id, service.MoveInput{
+		BoxID: req.BoxID,
+		Slot:  req.Slot,
+	})
+	if err != nil {
+		s.respondBoxError(c, "move pokemon", err)
+		return
+	}
+
+	log.Info("Pokemon moved", "user_id", userID, "pokemon_id", id, "box_id", req.BoxID, "slot", pokemon.BoxSlot)
+	c.JSON(http.StatusOK, pokemon)
+}
+
+func (s *Server) swapPokemonHandler(c *gin.Context) {
+	log := s.logger.With("handler", "swapPokemon")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, ok := idParam(c, "pokemon")
+	if !ok {
+		return
+	}
+
+	var req SwapPokemonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warn("Invalid JSON body", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pokemons, err := s.boxService.Swap(c.Request.Context(), userID.(int), id, req.PokemonID)
+	if err != nil {
+		s.respondBoxError(c, "swap pokemon", err)
+		return
+	}
+
+	log.Info("Pokemon swapped", "user_id", userID, "pokemon_id", id, "other_id", req.PokemonID)
+	c.JSON(http.StatusOK, gin.H{"data": pokemons})
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/bulk"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/service"
+)
+
+// importFormat takes ?format= if given and falls back to the Content-Type
+func importFormat(c *gin.Context) (bulk.Format, error) {
+	if format := c.Query("format"); format != "" {
+		return bulk.ParseFormat(format)
+	}
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	switch mediaType {
+	case "text/csv":
+		return bulk.FormatCSV, nil
+	case "application/x-ndjson", "application/jsonl":
+		return bulk.FormatNDJSON, nil
+	default:
+		return bulk.ParseFormat(mediaType)
+	}
+}
+
+// importPokemonHandler streams a CSV or NDJSON body into the collection.
+// ?mode=atomic (default) saves nothing if any row fails; ?mode=best_effort saves the valid rows.
+func (s *Server) importPokemonHandler(c *gin.Context) {
+	log := s.logger.With("handler", "importPokemon")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	format, err := importFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	mode := service.ImportMode(c.DefaultQuery("mode", string(service.ImportAtomic)))
+
+	rows, err := bulk.NewReader(format, c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := s.pokemonService.Import(c.Request.Context(), userID.(int), rows, mode)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		} else {
+			log.Error("Failed to import pokemon", "user_id", userID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		}
+		return
+	}
+
+	log.Info("Pokemon imported", "user_id", userID, "mode", mode, "imported", report.Imported, "failed", report.Failed)
+
+	// An atomic import with bad rows saved nothing, so it failed as a whole
+	if mode == service.ImportAtomic && report.Failed > 0 {
+		c.JSON(http.StatusBadRequest, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// exportPokemonHandler streams the whole collection as ?format=csv (default) or ndjson
+func (s *Server) exportPokemonHandler(c *gin.Context) {
+	log := s.logger.With("handler", "exportPokemon")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	format, err := bulk.ParseFormat(c.DefaultQuery("format", string(bulk.FormatCSV)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", `attachment; filename="pokedex.`+string(format)+`"`)
+
+	w, err := bulk.NewWriter(format, c.Writer)
+	if err == nil {
+		err = s.pokemonService.Export(c.Request.Context(), userID.(int), w)
+	}
+	if err != nil {
+		// The status line is already sent, so all we can do is cut the stream short
+		log.Error("Failed to export pokemon", "user_id", userID, "error", err)
+	}
+}
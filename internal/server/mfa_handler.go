@@ -0,0 +1,203 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/service"
+)
+
+type VerifyMFARequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"` // TOTP code or recovery code
+}
+
+func (s *Server) verifyMFAHandler(c *gin.Context) {
+	log := s.logger.With("handler", "verifyMFA")
+
+	var req VerifyMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warn("Invalid mfa verify payload", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	accessToken, refreshToken, err := s.authService.VerifyMFA(c.Request.Context(), req.MFAToken, req.Code, c.ClientIP())
+	var throttled *service.ThrottledError
+	switch {
+	case errors.As(err, &throttled):
+		log.Warn("MFA verification throttled", "client_ip", c.ClientIP(), "retry_after", throttled.RetryAfter)
+		respondThrottled(c, throttled)
+		return
+	case errors.Is(err, service.ErrUserDisabled):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account disabled"})
+		return
+	case errors.Is(err, service.ErrInvalidMFAToken):
+		// The challenge expired or is bogus; the client has to log in again
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired MFA token, please log in again"})
+		return
+	case errors.Is(err, service.ErrInvalidMFACode):
+		log.Warn("MFA verification failed", "client_ip", c.ClientIP())
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid code"})
+		return
+	case err != nil:
+		log.Error("MFA verification failed", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+
+	s.setRefreshCookie(c, refreshToken)
+
+	log.Info("User logged in with second factor")
+	c.JSON(http.StatusOK, gin.H{
+		"access_token": accessToken,
+		"message":      "Login successful",
+	})
+}
+
+type EnrollTOTPRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+func (s *Server) enrollTOTPHandler(c *gin.Context) {
+	log := s.logger.With("handler", "enrollTOTP")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req EnrollTOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	enrollment, err := s.authService.EnrollTOTP(c.Request.Context(), userID.(int), req.Password, c.ClientIP())
+	var throttled *service.ThrottledError
+	switch {
+	case errors.As(err, &throttled):
+		respondThrottled(c, throttled)
+		return
+	case errors.Is(err, service.ErrInvalidCredentials):
+		log.Warn("2FA enrollment refused", "user_id", userID)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid password"})
+		return
+	case errors.Is(err, service.ErrMFAAlreadyEnabled):
+		c.JSON(http.StatusConflict, gin.H{"error": "Two-factor authentication is already enabled"})
+		return
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	case err != nil:
+		log.Error("Failed to start 2FA enrollment", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+
+	log.Info("2FA enrollment started", "user_id", userID)
+	c.JSON(http.StatusOK, enrollment)
+}
+
+type ConfirmTOTPRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+func (s *Server) confirmTOTPHandler(c *gin.Context) {
+	log := s.logger.With("handler", "confirmTOTP")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req ConfirmTOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	recoveryCodes, err := s.authService.ConfirmTOTP(c.Request.Context(), userID.(int), req.Password, req.Code, c.ClientIP())
+	var throttled *service.ThrottledError
+	switch {
+	case errors.As(err, &throttled):
+		respondThrottled(c, throttled)
+		return
+	case errors.Is(err, service.ErrInvalidCredentials):
+		log.Warn("2FA confirmation refused", "user_id", userID)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid password"})
+		return
+	case errors.Is(err, service.ErrMFAAlreadyEnabled):
+		c.JSON(http.StatusConflict, gin.H{"error": "Two-factor authentication is already enabled"})
+		return
+	case errors.Is(err, service.ErrMFANotEnrolled):
+		c.JSON(http.StatusConflict, gin.H{"error": "Start enrollment first"})
+		return
+	case errors.Is(err, service.ErrInvalidMFACode):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid code"})
+		return
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	case err != nil:
+		log.Error("Failed to confirm 2FA", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+
+	log.Info("2FA enabled", "user_id", userID)
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Two-factor authentication enabled. Store the recovery codes somewhere safe, they are shown only once",
+		"recovery_codes": recoveryCodes,
+	})
+}
+
+type DisableTOTPRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"` // TOTP code or recovery code
+}
+
+func (s *Server) disableTOTPHandler(c *gin.Context) {
+	log := s.logger.With("handler", "disableTOTP")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req DisableTOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	err := s.authService.DisableTOTP(c.Request.Context(), userID.(int), req.Password, req.Code, c.ClientIP())
+	var throttled *service.ThrottledError
+	switch {
+	case errors.As(err, &throttled):
+		respondThrottled(c, throttled)
+		return
+	case errors.Is(err, service.ErrMFANotEnabled):
+		c.JSON(http.StatusConflict, gin.H{"error": "Two-factor authentication is not enabled"})
+		return
+	case errors.Is(err, service.ErrInvalidCredentials), errors.Is(err, service.ErrInvalidMFACode):
+		log.Warn("2FA disable refused", "user_id", userID, "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid password or code"})
+		return
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	case err != nil:
+		log.Error("Failed to disable 2FA", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+
+	log.Info("2FA disabled", "user_id", userID)
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
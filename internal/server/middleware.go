@@ -1,10 +1,13 @@
 package server
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/service"
 	"github.com/sanskarchoudhry/pokedex-backend/internal/utils"
 )
 
@@ -24,7 +27,7 @@ func (s *Server) AuthMiddleware() gin.HandlerFunc {
 
 		tokenString := parts[1]
 
-		claims, err := utils.ValidateToken(tokenString)
+		claims, err := s.jwt.ValidateToken(tokenString, utils.TokenTypeAccess)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
 			return
@@ -36,7 +39,74 @@ func (s *Server) AuthMiddleware() gin.HandlerFunc {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
 			return
 		}
+		verified, _ := claims["email_verified"].(bool)
+		c.Set("emailVerified", verified)
+		// Tokens from before roles existed carry none; those users are plain users
+		role, _ := claims["role"].(string)
+		if role == "" {
+			role = string(models.RoleUser)
+		}
+		c.Set("role", models.Role(role))
+
+		c.Next()
+	}
+}
+
+// RequireVerified blocks writes from users who haven't confirmed their email.
+// Reads stay open so a new user can look around. It must run after AuthMiddleware.
+func (s *Server) RequireVerified() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+
+		// The claim is as old as the access token, so a refresh picks up a fresh verification
+		if !c.GetBool("emailVerified") {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Email not verified. Verify it, then refresh your access token"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireRole lets through users whose role is min or ranks above it. It must
+// run after AuthMiddleware. The role comes from the access token, so a change
+// takes effect at the user's next refresh, unless RefreshRole ran first.
+func (s *Server) RequireRole(min models.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, _ := c.Get("role")
+		if r, _ := role.(models.Role); !r.AtLeast(min) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RefreshRole replaces the role from the access token with the stored one, and
+// turns away disabled accounts, so staff changes apply on the very next request.
+// It costs a query per request, so only the admin routes use it. It must run
+// after AuthMiddleware and before RequireRole.
+func (s *Server) RefreshRole() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, _ := c.Get("userID")
+		actor, err := s.adminService.Actor(c.Request.Context(), userID.(int))
+		switch {
+		case errors.Is(err, service.ErrUserNotFound):
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		case errors.Is(err, service.ErrUserDisabled):
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Account disabled"})
+			return
+		case err != nil:
+			s.logger.Error("Failed to load role", "user_id", userID, "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+			return
+		}
 
+		c.Set("role", actor.Role)
 		c.Next()
 	}
 }
@@ -2,19 +2,38 @@ package server
 
 import (
 	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
 	"github.com/sanskarchoudhry/pokedex-backend/internal/service"
 )
 
+// CreatePokemonRequest names the species plus what the trainer chose; name, type,
+// measurements and base stats come from the species catalog.
 type CreatePokemonRequest struct {
-	PokedexID int    `json:"pokedex_id" binding:"required"`
-	Name      string `json:"name" binding:"required"`
-	Nickname  string `json:"nickname"` // Optional
-	Type      string `json:"type" binding:"required"`
-	Height    int    `json:"height" binding:"required"`
-	Weight    int    `json:"weight" binding:"required"`
+	PokedexID  int               `json:"pokedex_id" binding:"required"`
+	Nickname   string            `json:"nickname"` // Optional
+	Level      int               `json:"level"`    // Optional, defaults to 5
+	Nature     string            `json:"nature"`   // Optional, defaults to a neutral nature
+	Ability    string            `json:"ability"`  // Optional, defaults to the species' first ability
+	IVs        models.StatSpread `json:"ivs"`
+	EVs        models.StatSpread `json:"evs"`
+	HeldItem   string            `json:"held_item"`
+	Moves      []string          `json:"moves"`      // Up to four
+	Friendship int               `json:"friendship"` // Optional, defaults to 70
+	Tags       []string          `json:"tags"`
+
+	Shiny           bool       `json:"shiny"`
+	Gender          string     `json:"gender"` // Optional, rolled from the species' gender rate
+	Form            string     `json:"form"`   // Optional, e.g. "alola"
+	Ball            string     `json:"ball"`   // Optional, defaults to "poke-ball"
+	OriginalTrainer string     `json:"original_trainer"`
+	CaughtAt        *time.Time `json:"caught_at"` // Optional, defaults to now
+	CaughtLocation  string     `json:"caught_location"`
 }
 
 func (s *Server) createPokemonHandler(c *gin.Context) {
@@ -36,16 +55,27 @@ func (s *Server) createPokemonHandler(c *gin.Context) {
 		return
 	}
 
-	pokemon, err := s.pokemonService.Create(
-		c.Request.Context(),
-		userID.(int),
-		req.PokedexID,
-		req.Name,
-		req.Nickname,
-		req.Type,
-		req.Height,
-		req.Weight,
-	)
+	pokemon, err := s.pokemonService.Create(c.Request.Context(), userID.(int), service.CreatePokemonInput{
+		PokedexID:  req.PokedexID,
+		Nickname:   req.Nickname,
+		Level:      req.Level,
+		Nature:     req.Nature,
+		Ability:    req.Ability,
+		IVs:        req.IVs,
+		EVs:        req.EVs,
+		HeldItem:   req.HeldItem,
+		Moves:      req.Moves,
+		Friendship: req.Friendship,
+		Tags:       req.Tags,
+
+		Shiny:           req.Shiny,
+		Gender:          req.Gender,
+		Form:            req.Form,
+		Ball:            req.Ball,
+		OriginalTrainer: req.OriginalTrainer,
+		CaughtAt:        req.CaughtAt,
+		CaughtLocation:  req.CaughtLocation,
+	})
 
 	if err != nil {
 		// Check if it's a Validation Error or a System Error
@@ -63,7 +93,63 @@ func (s *Server) createPokemonHandler(c *gin.Context) {
 	c.JSON(http.StatusCreated, pokemon)
 }
 
+// ListPokemonRequest holds the query string of the list endpoint
+type ListPokemonRequest struct {
+	Limit         int        `form:"limit"`
+	Cursor        string     `form:"cursor"`
+	Type          string     `form:"type"`
+	MinPokedexID  int        `form:"min_pokedex_id"`
+	MaxPokedexID  int        `form:"max_pokedex_id"`
+	NamePrefix    string     `form:"name_prefix"`
+	CreatedAfter  *time.Time `form:"created_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBefore *time.Time `form:"created_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	Box           int        `form:"box"`
+	Tag           string     `form:"tag"`
+
+	Shiny           *bool      `form:"shiny"`
+	Gender          string     `form:"gender"`
+	Form            string     `form:"form"`
+	Ball            string     `form:"ball"`
+	OriginalTrainer string     `form:"original_trainer"`
+	CaughtLocation  string     `form:"caught_location"`
+	CaughtAfter     *time.Time `form:"caught_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	CaughtBefore    *time.Time `form:"caught_before" time_format:"2006-01-02T15:04:05Z07:00"`
+
+	Sort  string `form:"sort"` // name, pokedex_id, weight, height or created_at
+	Order string `form:"order" binding:"omitempty,oneof=asc desc"`
+}
+
+// options maps the query string onto the service's list options
+func (req ListPokemonRequest) options() models.PokemonListOptions {
+	return models.PokemonListOptions{
+		Type:          models.PokemonType(req.Type),
+		MinPokedexID:  req.MinPokedexID,
+		MaxPokedexID:  req.MaxPokedexID,
+		NamePrefix:    req.NamePrefix,
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+		BoxID:         req.Box,
+		Tag:           req.Tag,
+
+		Shiny:           req.Shiny,
+		Gender:          models.Gender(req.Gender),
+		Form:            req.Form,
+		Ball:            req.Ball,
+		OriginalTrainer: req.OriginalTrainer,
+		CaughtLocation:  req.CaughtLocation,
+		CaughtAfter:     req.CaughtAfter,
+		CaughtBefore:    req.CaughtBefore,
+
+		SortBy:     models.PokemonSortField(req.Sort),
+		Descending: req.Order == "desc",
+		Limit:      req.Limit,
+		Cursor:     req.Cursor,
+	}
+}
+
 func (s *Server) listPokemonHandler(c *gin.Context) {
+	log := s.logger.With("handler", "listPokemon")
+
 	// 1. Get User ID from Context
 	userID, exists := c.Get("userID")
 	if !exists {
@@ -71,13 +157,354 @@ func (s *Server) listPokemonHandler(c *gin.Context) {
 		return
 	}
 
+	var req ListPokemonRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// 2. Call Service
-	list, err := s.pokemonService.List(c.Request.Context(), userID.(int))
+	page, err := s.pokemonService.List(c.Request.Context(), userID.(int), req.options())
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Error("Failed to list pokemon", "user_id", userID, "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pokemon"})
 		return
 	}
 
 	// 3. Return JSON
-	c.JSON(http.StatusOK, gin.H{"data": list})
+	c.JSON(http.StatusOK, page)
+}
+
+// DexProgressRequest holds the query string of the progress endpoint
+type DexProgressRequest struct {
+	Generation int    `form:"generation"` // Only list missing species from this generation
+	Limit      int    `form:"limit"`
+	Cursor     string `form:"cursor"`
+}
+
+func (s *Server) dexProgressHandler(c *gin.Context) {
+	log := s.logger.With("handler", "dexProgress")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req DexProgressRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	progress, err := s.pokemonService.Progress(c.Request.Context(), userID.(int), models.DexProgressOptions{
+		GenerationID: req.Generation,
+		Limit:        req.Limit,
+		Cursor:       req.Cursor,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Error("Failed to compute progress", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute progress"})
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// ActivityRequest holds the query string of the activity feed
+type ActivityRequest struct {
+	Limit  int    `form:"limit"`
+	Cursor string `form:"cursor"`
+}
+
+func (s *Server) activityHandler(c *gin.Context) {
+	log := s.logger.With("handler", "activity")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req ActivityRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := s.pokemonService.Activity(c.Request.Context(), userID.(int), models.ActivityOptions{
+		Limit:  req.Limit,
+		Cursor: req.Cursor,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Error("Failed to list activity", "user_id", userID, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch activity"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// idParam reads the :id path segment, answering 400 itself when it's not a positive integer.
+func idParam(c *gin.Context, resource string) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + resource + " id"})
+		return 0, false
+	}
+	return id, true
+}
+
+func (s *Server) getPokemonHandler(c *gin.Context) {
+	log := s.logger.With("handler", "getPokemon")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, ok := idParam(c, "pokemon")
+	if !ok {
+		return
+	}
+
+	pokemon, err := s.pokemonService.Get(c.Request.Context(), userID.(int), id)
+	if err != nil {
+		if errors.Is(err, service.ErrPokemonNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pokemon not found"})
+		} else {
+			log.Error("Failed to fetch pokemon", "user_id", userID, "pokemon_id", id, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, pokemon)
+}
+
+func (s *Server) pokemonHistoryHandler(c *gin.Context) {
+	log := s.logger.With("handler", "pokemonHistory")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, ok := idParam(c, "pokemon")
+	if !ok {
+		return
+	}
+
+	events, err := s.pokemonService.History(c.Request.Context(), userID.(int), id)
+	if err != nil {
+		if errors.Is(err, service.ErrPokemonNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pokemon not found"})
+		} else {
+			log.Error("Failed to fetch history", "user_id", userID, "pokemon_id", id, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": events})
+}
+
+// UpdatePokemonRequest is a partial update; omitted fields keep their current value.
+// IVs and EVs replace the whole spread, so omitted stats become 0.
+type UpdatePokemonRequest struct {
+	Nickname   *string            `json:"nickname"`
+	Level      *int               `json:"level"`
+	Nature     *string            `json:"nature"`
+	Ability    *string            `json:"ability"`
+	IVs        *models.StatSpread `json:"ivs"`
+	EVs        *models.StatSpread `json:"evs"`
+	HeldItem   *string            `json:"held_item"`
+	Moves      *[]string          `json:"moves"` // Replaces the whole moveset
+	Friendship *int               `json:"friendship"`
+	Tags       *[]string          `json:"tags"` // Replaces every tag
+
+	Shiny           *bool      `json:"shiny"`
+	Gender          *string    `json:"gender"`
+	Form            *string    `json:"form"`
+	Ball            *string    `json:"ball"`
+	OriginalTrainer *string    `json:"original_trainer"`
+	CaughtAt        *time.Time `json:"caught_at"`
+	CaughtLocation  *string    `json:"caught_location"`
+}
+
+func (s *Server) updatePokemonHandler(c *gin.Context) {
+	log := s.logger.With("handler", "updatePokemon")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, ok := idParam(c, "pokemon")
+	if !ok {
+		return
+	}
+
+	var req UpdatePokemonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warn("Invalid JSON body", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pokemon, err := s.pokemonService.Update(c.Request.Context(), userID.(int), id, service.UpdatePokemonInput{
+		Nickname:   req.Nickname,
+		Level:      req.Level,
+		Nature:     req.Nature,
+		Ability:    req.Ability,
+		IVs:        req.IVs,
+		EVs:        req.EVs,
+		HeldItem:   req.HeldItem,
+		Moves:      req.Moves,
+		Friendship: req.Friendship,
+		Tags:       req.Tags,
+
+		Shiny:           req.Shiny,
+		Gender:          req.Gender,
+		Form:            req.Form,
+		Ball:            req.Ball,
+		OriginalTrainer: req.OriginalTrainer,
+		CaughtAt:        req.CaughtAt,
+		CaughtLocation:  req.CaughtLocation,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPokemonNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pokemon not found"})
+		case errors.Is(err, service.ErrInvalidInput):
+			log.Info("Validation failed", "user_id", userID, "error", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			log.Error("Failed to update pokemon", "user_id", userID, "pokemon_id", id, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		}
+		return
+	}
+
+	log.Info("Pokemon updated", "user_id", userID, "pokemon_id", pokemon.ID)
+	c.JSON(http.StatusOK, pokemon)
+}
+
+func (s *Server) deletePokemonHandler(c *gin.Context) {
+	log := s.logger.With("handler", "deletePokemon")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, ok := idParam(c, "pokemon")
+	if !ok {
+		return
+	}
+
+	if err := s.pokemonService.Delete(c.Request.Context(), userID.(int), id); err != nil {
+		if errors.Is(err, service.ErrPokemonNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pokemon not found"})
+		} else {
+			log.Error("Failed to release pokemon", "user_id", userID, "pokemon_id", id, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		}
+		return
+	}
+
+	log.Info("Pokemon released", "user_id", userID, "pokemon_id", id)
+	c.Status(http.StatusNoContent)
+}
+
+func (s *Server) pokemonWeaknessesHandler(c *gin.Context) {
+	log := s.logger.With("handler", "pokemonWeaknesses")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, ok := idParam(c, "pokemon")
+	if !ok {
+		return
+	}
+
+	profile, err := s.pokemonService.Weaknesses(c.Request.Context(), userID.(int), id)
+	if err != nil {
+		if errors.Is(err, service.ErrPokemonNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pokemon not found"})
+		} else {
+			log.Error("Failed to compute weaknesses", "user_id", userID, "pokemon_id", id, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// EvolvePokemonRequest picks the evolution; both fields are optional
+type EvolvePokemonRequest struct {
+	Into int    `json:"into"` // Species id, needed when several evolutions are possible
+	Item string `json:"item"` // Item used, e.g. "fire-stone"
+}
+
+func (s *Server) evolvePokemonHandler(c *gin.Context) {
+	log := s.logger.With("handler", "evolvePokemon")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, ok := idParam(c, "pokemon")
+	if !ok {
+		return
+	}
+
+	var req EvolvePokemonRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		log.Warn("Invalid JSON body", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pokemon, err := s.pokemonService.Evolve(c.Request.Context(), userID.(int), id, service.EvolveInput{
+		IntoSpeciesID: req.Into,
+		Item:          req.Item,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPokemonNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pokemon not found"})
+		case errors.Is(err, service.ErrInvalidInput):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			log.Error("Failed to evolve pokemon", "user_id", userID, "pokemon_id", id, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		}
+		return
+	}
+
+	log.Info("Pokemon evolved", "user_id", userID, "pokemon_id", pokemon.ID, "into", pokemon.Name)
+	c.JSON(http.StatusOK, pokemon)
 }
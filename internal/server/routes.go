@@ -4,10 +4,19 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
 )
 
 func (s *Server) RegisterRoutes() http.Handler {
 	r := gin.Default()
+	// Login throttling keys on the client IP, so only named proxies may override it
+	if err := r.SetTrustedProxies(s.config.TrustedProxies); err != nil {
+		s.logger.Error("Invalid trusted proxies, trusting none", "error", err)
+		r.SetTrustedProxies(nil)
+	}
+
+	// Public keys so other services can verify our access tokens
+	r.GET("/.well-known/jwks.json", s.jwksHandler)
 
 	v1 := r.Group("/api/v1")
 	{
@@ -16,12 +25,29 @@ func (s *Server) RegisterRoutes() http.Handler {
 			auth.POST("/register", s.registerHandler)
 			auth.POST("/login", s.loginHandler)
 			auth.POST("/refresh", s.refreshHandler)
+			auth.POST("/logout", s.logoutHandler)
+			auth.POST("/logout-all", s.AuthMiddleware(), s.logoutAllHandler)
+			auth.POST("/password/forgot", s.forgotPasswordHandler)
+			auth.POST("/password/reset", s.resetPasswordHandler)
+			auth.POST("/verify", s.verifyEmailHandler)
+			auth.POST("/verify/resend", s.AuthMiddleware(), s.resendVerificationHandler)
+
+			// Enrolling and disabling need a session; verify finishes a login that has none yet
+			auth.POST("/mfa/verify", s.verifyMFAHandler)
+			auth.POST("/mfa/enroll", s.AuthMiddleware(), s.enrollTOTPHandler)
+			auth.POST("/mfa/confirm", s.AuthMiddleware(), s.confirmTOTPHandler)
+			auth.POST("/mfa/disable", s.AuthMiddleware(), s.disableTOTPHandler)
 		}
 
+		// Type chart and species are reference data, no login needed
+		v1.GET("/types/matchup", s.typeMatchupHandler)
+		v1.GET("/species/:id/evolutions", s.speciesEvolutionsHandler)
+
 		// Protected Routes
 		// We create a new group and apply the Middleware
+		// Reads are open to everyone logged in; writes need a verified email
 		protected := v1.Group("/pokedex")
-		protected.Use(s.AuthMiddleware())
+		protected.Use(s.AuthMiddleware(), s.RequireVerified())
 		{
 			protected.GET("/me", func(c *gin.Context) {
 				// Retrieve the UserID we set in the middleware
@@ -35,6 +61,75 @@ func (s *Server) RegisterRoutes() http.Handler {
 			// Pokemon Routes
 			protected.POST("/", s.createPokemonHandler)
 			protected.GET("/", s.listPokemonHandler)
+			protected.POST("/import", s.importPokemonHandler)
+			protected.GET("/export", s.exportPokemonHandler)
+			protected.GET("/progress", s.dexProgressHandler)
+			protected.GET("/activity", s.activityHandler)
+			protected.GET("/:id", s.getPokemonHandler)
+			protected.PATCH("/:id", s.updatePokemonHandler)
+			protected.DELETE("/:id", s.deletePokemonHandler)
+			protected.GET("/:id/weaknesses", s.pokemonWeaknessesHandler)
+			protected.GET("/:id/history", s.pokemonHistoryHandler)
+			protected.POST("/:id/evolve", s.evolvePokemonHandler)
+			protected.POST("/:id/move", s.movePokemonHandler)
+			protected.POST("/:id/swap", s.swapPokemonHandler)
+		}
+
+		// Teams only point at Pokemon the user already has, so they don't need a verified email
+		teams := v1.Group("/teams")
+		teams.Use(s.AuthMiddleware())
+		{
+			teams.POST("/", s.createTeamHandler)
+			teams.GET("/", s.listTeamsHandler)
+			teams.GET("/:id", s.getTeamHandler)
+			teams.PATCH("/:id", s.updateTeamHandler)
+			teams.DELETE("/:id", s.deleteTeamHandler)
+			teams.GET("/:id/analysis", s.teamAnalysisHandler)
+			teams.GET("/:id/export", s.exportTeamHandler)
+		}
+
+		// Boxes hold the Pokemon's places, so like the Pokedex writes need a verified email
+		boxes := v1.Group("/boxes")
+		boxes.Use(s.AuthMiddleware(), s.RequireVerified())
+		{
+			boxes.POST("/", s.createBoxHandler)
+			boxes.GET("/", s.listBoxesHandler)
+			boxes.GET("/:id", s.getBoxHandler)
+			boxes.PATCH("/:id", s.renameBoxHandler)
+			boxes.DELETE("/:id", s.deleteBoxHandler)
+		}
+
+		// Only the recipient can accept or decline; only the proposer can cancel.
+		// Accepting moves Pokemon between collections, so writes need a verified email
+		trades := v1.Group("/trades")
+		trades.Use(s.AuthMiddleware(), s.RequireVerified())
+		{
+			trades.POST("/", s.proposeTradeHandler)
+			trades.GET("/", s.listTradesHandler)
+			trades.GET("/:id", s.getTradeHandler)
+			trades.POST("/:id/accept", s.acceptTradeHandler)
+			trades.POST("/:id/decline", s.declineTradeHandler)
+			trades.POST("/:id/cancel", s.cancelTradeHandler)
+		}
+
+		imports := v1.Group("/import")
+		imports.Use(s.AuthMiddleware(), s.RequireVerified())
+		{
+			imports.POST("/showdown", s.importShowdownHandler)
+		}
+
+		// Moderators can look around and sign people out; switching accounts off
+		// and reading the audit log takes an admin. Roles are checked against the
+		// database, not the token, so a demotion can't ride out the token's lifetime
+		admin := v1.Group("/admin")
+		admin.Use(s.AuthMiddleware(), s.RefreshRole(), s.RequireRole(models.RoleModerator))
+		{
+			admin.GET("/users", s.adminListUsersHandler)
+			admin.GET("/users/:id/pokemon", s.adminUserCollectionHandler)
+			admin.POST("/users/:id/logout", s.adminForceLogoutHandler)
+			admin.POST("/users/:id/disable", s.RequireRole(models.RoleAdmin), s.adminDisableUserHandler)
+			admin.POST("/users/:id/enable", s.RequireRole(models.RoleAdmin), s.adminEnableUserHandler)
+			admin.GET("/audit-log", s.RequireRole(models.RoleAdmin), s.adminAuditLogHandler)
 		}
 	}
 
@@ -4,25 +4,43 @@ import (
 	"context"
 	"log/slog"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/sanskarchoudhry/pokedex-backend/internal/config"
 	"github.com/sanskarchoudhry/pokedex-backend/internal/service"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/utils"
 )
 
 type Server struct {
 	config         *config.Config
 	logger         *slog.Logger
+	jwt            *utils.JWTManager
 	authService    service.AuthService
 	pokemonService service.PokemonService
+	teamService    service.TeamService
+	speciesService service.SpeciesService
+	tradeService   service.TradeService
+	boxService     service.BoxService
+	adminService   service.AdminService
 	httpServer     *http.Server
+	background     sync.WaitGroup // Work started by requests that outlives them
 }
 
-func NewServer(cfg *config.Config, logger *slog.Logger, authService service.AuthService, pokeSvc service.PokemonService) *Server {
+// backgroundTimeout bounds work handed to runInBackground
+const backgroundTimeout = 30 * time.Second
+
+func NewServer(cfg *config.Config, logger *slog.Logger, jwtManager *utils.JWTManager, authService service.AuthService, pokeSvc service.PokemonService, teamSvc service.TeamService, speciesSvc service.SpeciesService, tradeSvc service.TradeService, boxSvc service.BoxService, adminSvc service.AdminService) *Server {
 	return &Server{
 		config:         cfg,
+		jwt:            jwtManager,
 		authService:    authService,
 		pokemonService: pokeSvc,
+		teamService:    teamSvc,
+		speciesService: speciesSvc,
+		tradeService:   tradeSvc,
+		boxService:     boxSvc,
+		adminService:   adminSvc,
 		logger:         logger,
 	}
 }
@@ -35,9 +53,39 @@ func (s *Server) Start() error {
 		WriteTimeout: 10 * time.Second,
 	}
 
+	s.httpServer = httpServer
 	return httpServer.ListenAndServe()
 }
 
+// Shutdown stops accepting requests, then waits for background work such as
+// queued emails until ctx runs out.
 func (s *Server) Shutdown(ctx context.Context) error {
-	return s.httpServer.Shutdown(ctx)
+	err := s.httpServer.Shutdown(ctx)
+
+	done := make(chan struct{})
+	go func() {
+		s.background.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		if err == nil {
+			err = ctx.Err()
+		}
+	}
+	return err
+}
+
+// runInBackground runs fn off the request path, for work the client shouldn't
+// wait on or be able to time. fn gets its own context, since the request's ends
+// with the response.
+func (s *Server) runInBackground(fn func(ctx context.Context)) {
+	s.background.Add(1)
+	go func() {
+		defer s.background.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), backgroundTimeout)
+		defer cancel()
+		fn(ctx)
+	}()
 }
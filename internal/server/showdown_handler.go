@@ -0,0 +1,77 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/service"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/showdown"
+)
+
+type ImportShowdownRequest struct {
+	Paste string `json:"paste" binding:"required"`
+}
+
+func (s *Server) importShowdownHandler(c *gin.Context) {
+	log := s.logger.With("handler", "importShowdown")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req ImportShowdownRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warn("Invalid JSON body", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	pokemons, err := s.pokemonService.ImportShowdown(c.Request.Context(), userID.(int), req.Paste)
+	if err != nil {
+		// Report every bad line so the whole paste can be fixed in one go
+		var parseErr *showdown.ParseError
+		switch {
+		case errors.As(err, &parseErr):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid paste", "lines": parseErr.Errors})
+		case errors.Is(err, service.ErrInvalidInput):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			log.Error("Failed to import paste", "user_id", userID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		}
+		return
+	}
+
+	log.Info("Showdown paste imported", "user_id", userID, "count", len(pokemons))
+	c.JSON(http.StatusCreated, gin.H{"data": pokemons})
+}
+
+// exportTeamHandler renders a team in the requested format. Only Showdown pastes are supported.
+func (s *Server) exportTeamHandler(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, ok := idParam(c, "team")
+	if !ok {
+		return
+	}
+
+	if format := c.DefaultQuery("format", "showdown"); format != "showdown" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported export format: " + format})
+		return
+	}
+
+	team, err := s.teamService.Get(c.Request.Context(), userID.(int), id)
+	if err != nil {
+		s.respondTeamError(c, "export team", err)
+		return
+	}
+
+	c.String(http.StatusOK, showdown.Format(team.Members))
+}
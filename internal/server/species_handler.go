@@ -0,0 +1,31 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/service"
+)
+
+func (s *Server) speciesEvolutionsHandler(c *gin.Context) {
+	log := s.logger.With("handler", "speciesEvolutions")
+
+	id, ok := idParam(c, "species")
+	if !ok {
+		return
+	}
+
+	chain, err := s.speciesService.EvolutionChain(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrSpeciesNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Species not found"})
+		} else {
+			log.Error("Failed to load evolution chain", "species_id", id, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, chain)
+}
@@ -0,0 +1,174 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/service"
+)
+
+type CreateTeamRequest struct {
+	Name       string `json:"name" binding:"required"`
+	PokemonIDs []int  `json:"pokemon_ids"` // In slot order, up to six
+}
+
+// UpdateTeamRequest is a partial update; sending pokemon_ids replaces the whole party.
+type UpdateTeamRequest struct {
+	Name       *string `json:"name"`
+	PokemonIDs *[]int  `json:"pokemon_ids"`
+}
+
+// respondTeamError maps service errors to status codes
+func (s *Server) respondTeamError(c *gin.Context, action string, err error) {
+	switch {
+	case errors.Is(err, service.ErrTeamNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+	case errors.Is(err, service.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		s.logger.Error("Failed to "+action, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+	}
+}
+
+func (s *Server) createTeamHandler(c *gin.Context) {
+	log := s.logger.With("handler", "createTeam")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req CreateTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warn("Invalid JSON body", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	team, err := s.teamService.Create(c.Request.Context(), userID.(int), req.Name, req.PokemonIDs)
+	if err != nil {
+		s.respondTeamError(c, "create team", err)
+		return
+	}
+
+	log.Info("Team created", "user_id", userID, "team_id", team.ID)
+	c.JSON(http.StatusCreated, team)
+}
+
+func (s *Server) listTeamsHandler(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	teams, err := s.teamService.List(c.Request.Context(), userID.(int))
+	if err != nil {
+		s.respondTeamError(c, "list teams", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": teams})
+}
+
+func (s *Server) getTeamHandler(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, ok := idParam(c, "team")
+	if !ok {
+		return
+	}
+
+	team, err := s.teamService.Get(c.Request.Context(), userID.(int), id)
+	if err != nil {
+		s.respondTeamError(c, "fetch team", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, team)
+}
+
+func (s *Server) updateTeamHandler(c *gin.Context) {
+	log := s.logger.With("handler", "updateTeam")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, ok := idParam(c, "team")
+	if !ok {
+		return
+	}
+
+	var req UpdateTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warn("Invalid JSON body", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	team, err := s.teamService.Update(c.Request.Context(), userID.(int), id, service.UpdateTeamInput{
+		Name:       req.Name,
+		PokemonIDs: req.PokemonIDs,
+	})
+	if err != nil {
+		s.respondTeamError(c, "update team", err)
+		return
+	}
+
+	log.Info("Team updated", "user_id", userID, "team_id", team.ID)
+	c.JSON(http.StatusOK, team)
+}
+
+func (s *Server) deleteTeamHandler(c *gin.Context) {
+	log := s.logger.With("handler", "deleteTeam")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, ok := idParam(c, "team")
+	if !ok {
+		return
+	}
+
+	if err := s.teamService.Delete(c.Request.Context(), userID.(int), id); err != nil {
+		s.respondTeamError(c, "delete team", err)
+		return
+	}
+
+	log.Info("Team deleted", "user_id", userID, "team_id", id)
+	c.Status(http.StatusNoContent)
+}
+
+func (s *Server) teamAnalysisHandler(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, ok := idParam(c, "team")
+	if !ok {
+		return
+	}
+
+	analysis, err := s.teamService.Analyze(c.Request.Context(), userID.(int), id)
+	if err != nil {
+		s.respondTeamError(c, "analyze team", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, analysis)
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/service"
+)
+
+// ProposeTradeRequest offers one of your Pokemon for one of the recipient's
+type ProposeTradeRequest struct {
+	OfferedPokemonID   int `json:"offered_pokemon_id" binding:"required"`
+	RecipientID        int `json:"recipient_id" binding:"required"`
+	RequestedPokemonID int `json:"requested_pokemon_id" binding:"required"`
+}
+
+// respondTradeError maps service errors to status codes
+func (s *Server) respondTradeError(c *gin.Context, action string, err error) {
+	switch {
+	case errors.Is(err, service.ErrTradeNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trade not found"})
+	case errors.Is(err, service.ErrTradeConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		s.logger.Error("Failed to "+action, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+	}
+}
+
+func (s *Server) proposeTradeHandler(c *gin.Context) {
+	log := s.logger.With("handler", "proposeTrade")
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req ProposeTradeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Warn("Invalid JSON body", "error", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	trade, err := s.tradeService.Propose(c.Request.Context(), userID.(int), service.ProposeTradeInput{
+		OfferedPokemonID:   req.OfferedPokemonID,
+		RecipientID:        req.RecipientID,
+		RequestedPokemonID: req.RequestedPokemonID,
+	})
+	if err != nil {
+		s.respondTradeError(c, "propose trade", err)
+		return
+	}
+
+	log.Info("Trade proposed", "user_id", userID, "trade_id", trade.ID, "recipient_id", trade.RecipientID)
+	c.JSON(http.StatusCreated, trade)
+}
+
+// listTradesHandler returns trades the user sent or received, optionally filtered by ?status=
+func (s *Server) listTradesHandler(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	trades, err := s.tradeService.List(c.Request.Context(), userID.(int), c.Query("status"))
+	if err != nil {
+		s.respondTradeError(c, "list trades", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": trades})
+}
+
+func (s *Server) getTradeHandler(c *gin.Context) {
+	s.tradeAction(c, "fetch trade", s.tradeService.Get)
+}
+
+func (s *Server) acceptTradeHandler(c *gin.Context) {
+	s.tradeAction(c, "accept trade", s.tradeService.Accept)
+}
+
+func (s *Server) declineTradeHandler(c *gin.Context) {
+	s.tradeAction(c, "decline trade", s.tradeService.Decline)
+}
+
+func (s *Server) cancelTradeHandler(c *gin.Context) {
+	s.tradeAction(c, "cancel trade", s.tradeService.Cancel)
+}
+
+// tradeAction runs a service call on the trade named in the path and returns the trade
+func (s *Server) tradeAction(c *gin.Context, action string, call func(ctx context.Context, userId, id int) (*models.Trade, error)) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, ok := idParam(c, "trade")
+	if !ok {
+		return
+	}
+
+	trade, err := call(c.Request.Context(), userID.(int), id)
+	if err != nil {
+		s.respondTradeError(c, action, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, trade)
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/typechart"
+)
+
+type MatchupRequest struct {
+	Attack string `form:"attack" binding:"required"`
+	Defend string `form:"defend" binding:"required"` // One or two comma separated types
+}
+
+// typeMatchupHandler answers GET /types/matchup?attack=fire&defend=grass,poison
+func (s *Server) typeMatchupHandler(c *gin.Context) {
+	var req MatchupRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	attack, err := models.ParsePokemonType(req.Attack)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	parts := strings.Split(req.Defend, ",")
+	if len(parts) > 2 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "defend takes at most two types"})
+		return
+	}
+
+	var defend models.Types
+	if defend.Primary, err = models.ParsePokemonType(parts[0]); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(parts) == 2 {
+		if defend.Secondary, err = models.ParsePokemonType(parts[1]); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if defend.Secondary == defend.Primary {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "defending types must differ"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"attack":     attack,
+		"defend":     defend,
+		"multiplier": typechart.Effectiveness(attack, defend),
+	})
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/repository"
+)
+
+// ErrInsufficientRole means the actor's role doesn't reach far enough for the action.
+var ErrInsufficientRole = errors.New("insufficient role")
+
+// Actor is the staff member behind an admin request, with their role as stored.
+type Actor struct {
+	UserID int
+	Role   models.Role
+}
+
+// AdminService lets staff look after other accounts. Every successful call,
+// reads included, leaves an entry in the audit log, and so does every action
+// refused for the target's rank.
+type AdminService interface {
+	Actor(ctx context.Context, userID int) (Actor, error)
+	ListUsers(ctx context.Context, actor Actor, opts models.UserListOptions) (*models.UserPage, error)
+	DisableUser(ctx context.Context, actor Actor, userID int) error
+	EnableUser(ctx context.Context, actor Actor, userID int) error
+	ForceLogout(ctx context.Context, actor Actor, userID int) error
+	UserCollection(ctx context.Context, actor Actor, userID int, opts models.PokemonListOptions) (*models.PokemonPage, error)
+	AuditLog(ctx context.Context, opts models.AuditLogOptions) (*models.AuditLogPage, error)
+}
+
+type adminService struct {
+	userRepo       repository.UserRepository
+	tokenRepo      repository.TokenRepository
+	auditRepo      repository.AuditRepository
+	pokemonService PokemonService
+}
+
+func NewAdminService(userRepo repository.UserRepository, tokenRepo repository.TokenRepository, auditRepo repository.AuditRepository, pokemonService PokemonService) AdminService {
+	return &adminService{
+		userRepo:       userRepo,
+		tokenRepo:      tokenRepo,
+		auditRepo:      auditRepo,
+		pokemonService: pokemonService,
+	}
+}
+
+// Actor loads a staff member as the database has them now, so a demotion or
+// suspension takes effect on their next request rather than their next refresh.
+func (s *adminService) Actor(ctx context.Context, userID int) (Actor, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return Actor{}, fmt.Errorf("loading user: %w", err)
+	}
+	if user == nil {
+		return Actor{}, ErrUserNotFound
+	}
+	if user.IsDisabled() {
+		return Actor{}, ErrUserDisabled
+	}
+	return Actor{UserID: user.ID, Role: user.Role}, nil
+}
+
+// ListUsers pages through accounts, oldest first, optionally searching by email.
+func (s *adminService) ListUsers(ctx context.Context, actor Actor, opts models.UserListOptions) (*models.UserPage, error) {
+	if opts.Role != "" && !opts.Role.IsValid() {
+		return nil, fmt.Errorf("%w: unknown role %q", ErrInvalidInput, opts.Role)
+	}
+	limit, err := listLimit(opts.Limit)
+	if err != nil {
+		return nil, err
+	}
+	opts.Limit = limit
+
+	page, err := s.userRepo.ListUsers(ctx, opts)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidInput)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	if page.Data == nil {
+		page.Data = []models.User{}
+	}
+
+	// Record what was searched for, not what came back
+	details := map[string]any{}
+	if opts.Email != "" {
+		details["email"] = opts.Email
+	}
+	if opts.Role != "" {
+		details["role"] = opts.Role
+	}
+	if opts.Disabled != nil {
+		details["disabled"] = *opts.Disabled
+	}
+	if err := s.recordRead(ctx, auditEntry(actor, models.AuditListUsers, 0, details)); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// DisableUser switches the account off and revokes its refresh tokens. Access
+// tokens already issued keep working until they expire.
+func (s *adminService) DisableUser(ctx context.Context, actor Actor, userID int) error {
+	return s.setDisabled(ctx, actor, userID, true)
+}
+
+// EnableUser switches a disabled account back on.
+func (s *adminService) EnableUser(ctx context.Context, actor Actor, userID int) error {
+	return s.setDisabled(ctx, actor, userID, false)
+}
+
+func (s *adminService) setDisabled(ctx context.Context, actor Actor, userID int, disabled bool) error {
+	// A staff member could otherwise lock themselves out, or undo their own suspension
+	if userID == actor.UserID {
+		return fmt.Errorf("%w: you cannot change the status of your own account", ErrInvalidInput)
+	}
+	action := models.AuditEnableUser
+	if disabled {
+		action = models.AuditDisableUser
+	}
+	if err := s.checkCanManage(ctx, actor, userID, action); err != nil {
+		return err
+	}
+	found, err := s.userRepo.SetUserDisabled(ctx, userID, disabled, auditEntry(actor, action, userID, nil))
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	if !found {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// ForceLogout revokes every refresh token of the user, signing them out everywhere
+// once their access token expires.
+func (s *adminService) ForceLogout(ctx context.Context, actor Actor, userID int) error {
+	if err := s.checkCanManage(ctx, actor, userID, models.AuditForceLogout); err != nil {
+		return err
+	}
+	if err := s.tokenRepo.RevokeAllRefreshTokens(ctx, userID, auditEntry(actor, models.AuditForceLogout, userID, nil)); err != nil {
+		return fmt.Errorf("revoking refresh tokens: %w", err)
+	}
+	return nil
+}
+
+// UserCollection lists another user's Pokemon with the same options as their own list.
+func (s *adminService) UserCollection(ctx context.Context, actor Actor, userID int, opts models.PokemonListOptions) (*models.PokemonPage, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("loading user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	page, err := s.pokemonService.List(ctx, user.ID, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.recordRead(ctx, auditEntry(actor, models.AuditViewCollection, user.ID, nil)); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// AuditLog pages through the audit log, newest first. Reading it is not itself audited.
+func (s *adminService) AuditLog(ctx context.Context, opts models.AuditLogOptions) (*models.AuditLogPage, error) {
+	limit, err := listLimit(opts.Limit)
+	if err != nil {
+		return nil, err
+	}
+	opts.Limit = limit
+
+	page, err := s.auditRepo.ListAudit(ctx, opts)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidInput)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log: %w", err)
+	}
+	if page.Data == nil {
+		page.Data = []models.AuditEntry{}
+	}
+	return page, nil
+}
+
+// checkCanManage makes sure the target of an account action exists and ranks
+// below the actor. Admins can act on anyone, other admins included. Refusals
+// are audited, since they may be someone probing for more than their role allows.
+func (s *adminService) checkCanManage(ctx context.Context, actor Actor, userID int, action models.AuditAction) error {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("loading user: %w", err)
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	if !actor.Role.AtLeast(models.RoleAdmin) && user.Role.AtLeast(actor.Role) {
+		denied := auditEntry(actor, models.AuditDenied, userID, map[string]any{"action": action})
+		if err := s.auditRepo.RecordAudit(ctx, denied); err != nil {
+			return fmt.Errorf("recording audit entry: %w", err)
+		}
+		return ErrInsufficientRole
+	}
+	return nil
+}
+
+// auditEntry describes an action for the audit log. Changes hand it to the
+// repository so it commits with them; reads go through recordRead.
+func auditEntry(actor Actor, action models.AuditAction, targetUserID int, details map[string]any) models.AuditEntry {
+	entry := models.AuditEntry{
+		ActorID: &actor.UserID,
+		Action:  action,
+		Details: details,
+	}
+	if targetUserID != 0 {
+		entry.TargetUserID = &targetUserID
+	}
+	return entry
+}
+
+// recordRead audits a read. It runs before the data is handed out, so nothing
+// is seen without an entry.
+func (s *adminService) recordRead(ctx context.Context, entry models.AuditEntry) error {
+	if err := s.auditRepo.RecordAudit(ctx, entry); err != nil {
+		return fmt.Errorf("recording audit entry: %w", err)
+	}
+	return nil
+}
+
+// listLimit applies the usual default and cap to a page size.
+func listLimit(limit int) (int, error) {
+	if limit < 0 {
+		return 0, fmt.Errorf("%w: limit cannot be negative", ErrInvalidInput)
+	}
+	if limit == 0 {
+		return defaultListLimit, nil
+	}
+	return min(limit, maxListLimit), nil
+}
@@ -4,67 +4,230 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"time"
 
+	"github.com/sanskarchoudhry/pokedex-backend/internal/mailer"
 	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
 	"github.com/sanskarchoudhry/pokedex-backend/internal/repository"
 	"github.com/sanskarchoudhry/pokedex-backend/internal/utils"
 )
 
+// refreshTokenTTL is how long a refresh token (and its cookie) stays valid.
+const refreshTokenTTL = 7 * 24 * time.Hour
+
+// refreshReuseGrace is how long a rotated refresh token may still be exchanged,
+// so two tabs refreshing at once, or a retry after a lost response, don't look
+// like theft. Each exchange still gets its own successor in the same family.
+const refreshReuseGrace = 10 * time.Second
+
+// passwordResetTTL is how long an emailed reset link works.
+const passwordResetTTL = time.Hour
+
+// passwordResetInterval is the least time between two reset emails to one user.
+const passwordResetInterval = time.Minute
+
+// verificationResendInterval is the least time between two verification emails to one user.
+const verificationResendInterval = time.Minute
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenExpired = errors.New("refresh token expired")
+	// ErrRefreshTokenReused means an already-rotated token was presented again.
+	// The whole token family is revoked when this happens.
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrUserDisabled       = errors.New("user account is disabled")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrInvalidResetToken covers unknown, expired and already used reset tokens alike.
+	ErrInvalidResetToken = errors.New("invalid or expired reset token")
+
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+	ErrAlreadyVerified          = errors.New("email already verified")
+	// ErrThrottled is wrapped by *ThrottledError, which says when to try again.
+	ErrThrottled = errors.New("too many requests")
+)
+
+// ThrottledError means the caller must wait RetryAfter before trying again.
+type ThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("%v, retry in %s", ErrThrottled, e.RetryAfter.Round(time.Second))
+}
+
+func (e *ThrottledError) Unwrap() error {
+	return ErrThrottled
+}
+
 type AuthService interface {
 	Register(ctx context.Context, email, password string) (*models.User, error)
-	Login(ctx context.Context, email, password string) (string, string, error) // Returns (accessToken, refreshToken, error)
-	Refresh(ctx context.Context, rawRefreshToken string) (string, error)
+	Login(ctx context.Context, email, password, clientIP string) (*LoginResult, error)
+	Refresh(ctx context.Context, rawRefreshToken string) (string, string, error) // Returns (accessToken, newRefreshToken, error)
+	Logout(ctx context.Context, rawRefreshToken string) error
+	LogoutAll(ctx context.Context, userID int) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, rawResetToken, newPassword string) error
+	SendVerificationEmail(ctx context.Context, userID int) error
+	VerifyEmail(ctx context.Context, verificationToken string) error
+	VerifyMFA(ctx context.Context, mfaToken, code, clientIP string) (string, string, error) // Returns (accessToken, refreshToken, error)
+	EnrollTOTP(ctx context.Context, userID int, password, clientIP string) (*TOTPEnrollment, error)
+	ConfirmTOTP(ctx context.Context, userID int, password, code, clientIP string) ([]string, error) // Returns the recovery codes
+	DisableTOTP(ctx context.Context, userID int, password, code, clientIP string) error
 }
 
 type authService struct {
 	userRepo  repository.UserRepository
 	tokenRepo repository.TokenRepository
+	mfaRepo   repository.MFARepository
+	jwt       *utils.JWTManager
+	mailer    mailer.Mailer
+	appURL    string // Links in emails point here
+	throttle  *loginThrottle
 }
 
-func NewAuthService(userRepo repository.UserRepository, tokenRepo repository.TokenRepository) AuthService {
+func NewAuthService(userRepo repository.UserRepository, tokenRepo repository.TokenRepository, mfaRepo repository.MFARepository, jwtManager *utils.JWTManager, m mailer.Mailer, appURL string, attempts repository.LoginAttemptStore, policy LoginPolicy) AuthService {
 	return &authService{
 		userRepo:  userRepo,
 		tokenRepo: tokenRepo,
+		mfaRepo:   mfaRepo,
+		jwt:       jwtManager,
+		mailer:    m,
+		appURL:    appURL,
+		throttle:  &loginThrottle{store: attempts, policy: policy},
 	}
 }
 
-func (s *authService) Refresh(ctx context.Context, rawRefreshToken string) (string, error) {
+func (s *authService) Refresh(ctx context.Context, rawRefreshToken string) (string, string, error) {
 	// 1. Hash the token to look it up
 	tokenHash := utils.HashToken(rawRefreshToken)
 
 	// 2. Find it in the DB
 	refreshTokenModel, err := s.tokenRepo.GetRefreshToken(ctx, tokenHash)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 	if refreshTokenModel == nil {
-		return "", errors.New("invalid refresh token")
+		return "", "", ErrInvalidRefreshToken
+	}
+
+	// 3. Reuse Detection: a rotated token must never come back after the grace window.
+	// If it does, either the client or an attacker holds a stolen copy, so kill the whole family.
+	if refreshTokenModel.RotatedAt != nil && time.Since(*refreshTokenModel.RotatedAt) > refreshReuseGrace {
+		return "", "", s.revokeReusedFamily(ctx, refreshTokenModel)
 	}
 
-	// 3. Check Expiration
+	// 4. Check Expiration
 	if time.Now().After(refreshTokenModel.ExpiresAt) {
-		return "", errors.New("refresh token expired")
+		return "", "", ErrRefreshTokenExpired
 	}
 
-	// 4. Get the User (to put their email in the new JWT)
+	// 5. Reload the User so the new JWT carries real, current claims
+	user, err := s.userRepo.GetUserByID(ctx, refreshTokenModel.UserID)
+	if err != nil {
+		return "", "", fmt.Errorf("loading user: %w", err)
+	}
+	if user == nil {
+		return "", "", ErrUserNotFound
+	}
+	if user.IsDisabled() {
+		return "", "", ErrUserDisabled
+	}
 
-	//TODO:
-	// We can add a GetUserByID method to UserRepo, or just trust the ID in the token.
-	// For a Senior/Secure app, we SHOULD verify the user still exists.
-	// Let's assume you add GetUserByID to UserRepo. If not, we can skip email in JWT for now.
-	// For simplicity, let's just generate the token with the UserID.
+	newAccessToken, err := s.generateAccessToken(user)
+	if err != nil {
+		return "", "", fmt.Errorf("generating new access token: %w", err)
+	}
 
-	// Note: To be perfect, we need the Email for the JWT claims.
-	// Let's fetch the user quickly. (Assuming you have GetUserByID, if not, we can assume email is not strictly required for now)
-	// Let's just generate it with UserID for now to keep it moving.
+	// 6. Retire the old token and store its successor in the same family, together.
+	// Losing this race also means the token was replayed.
+	nextRefreshToken, successor, err := newRefreshToken(user.ID, refreshTokenModel.FamilyID)
+	if err != nil {
+		return "", "", err
+	}
+	rotated, err := s.tokenRepo.RotateRefreshToken(ctx, tokenHash, successor, refreshReuseGrace)
+	if err != nil {
+		return "", "", fmt.Errorf("rotating refresh token: %w", err)
+	}
+	if !rotated {
+		return "", "", s.revokeReusedFamily(ctx, refreshTokenModel)
+	}
+
+	return newAccessToken, nextRefreshToken, nil
+}
 
-	newAccessToken, err := utils.GenerateAccessToken(refreshTokenModel.UserID, "user@refreshed.com") // Placeholder email
+// generateAccessToken builds the JWT for a user. Login and Refresh both go through
+// here so tokens always carry the same claims.
+func (s *authService) generateAccessToken(user *models.User) (string, error) {
+	return s.jwt.GenerateAccessToken(user.ID, user.Email, user.IsVerified(), string(user.Role))
+}
+
+// revokeReusedFamily wipes out every token in the family of a replayed token
+// and reports the incident to the caller.
+func (s *authService) revokeReusedFamily(ctx context.Context, token *models.RefreshToken) error {
+	if err := s.tokenRepo.RevokeTokenFamily(ctx, token.FamilyID); err != nil {
+		return fmt.Errorf("revoking token family after reuse: %w", err)
+	}
+	return fmt.Errorf("%w: user_id=%d family_id=%s", ErrRefreshTokenReused, token.UserID, token.FamilyID)
+}
+
+// newRefreshToken generates a refresh token in the given family. It returns the
+// raw token for the client and the model to store.
+func newRefreshToken(userID int, familyID string) (string, *models.RefreshToken, error) {
+	rawRefreshToken, tokenHash, err := utils.GenerateRefreshToken()
 	if err != nil {
-		return "", fmt.Errorf("generating new access token: %w", err)
+		return "", nil, fmt.Errorf("generating refresh token: %w", err)
 	}
 
-	return newAccessToken, nil
+	return rawRefreshToken, &models.RefreshToken{
+		UserID:    userID,
+		TokenHash: tokenHash,
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+	}, nil
+}
+
+// issueRefreshToken creates and stores a new refresh token in the given family.
+func (s *authService) issueRefreshToken(ctx context.Context, userID int, familyID string) (string, error) {
+	rawRefreshToken, refreshTokenModel, err := newRefreshToken(userID, familyID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.tokenRepo.CreateRefreshToken(ctx, refreshTokenModel); err != nil {
+		return "", fmt.Errorf("saving refresh token: %w", err)
+	}
+
+	return rawRefreshToken, nil
+}
+
+// Logout revokes the refresh token belonging to the current session, along with
+// the rotated tokens that preceded it. Revoking an unknown token is not an error,
+// so logging out twice is harmless.
+func (s *authService) Logout(ctx context.Context, rawRefreshToken string) error {
+	tokenHash := utils.HashToken(rawRefreshToken)
+
+	refreshTokenModel, err := s.tokenRepo.GetRefreshToken(ctx, tokenHash)
+	if err != nil {
+		return fmt.Errorf("looking up refresh token: %w", err)
+	}
+	if refreshTokenModel == nil {
+		return nil
+	}
+
+	if err := s.tokenRepo.RevokeTokenFamily(ctx, refreshTokenModel.FamilyID); err != nil {
+		return fmt.Errorf("revoking refresh token: %w", err)
+	}
+	return nil
+}
+
+// LogoutAll revokes every refresh token issued to the user.
+func (s *authService) LogoutAll(ctx context.Context, userID int) error {
+	if err := s.tokenRepo.RevokeAllRefreshTokens(ctx, userID); err != nil {
+		return fmt.Errorf("revoking refresh tokens: %w", err)
+	}
+	return nil
 }
 
 func (s *authService) Register(ctx context.Context, email, password string) (*models.User, error) {
@@ -92,38 +255,207 @@ func (s *authService) Register(ctx context.Context, email, password string) (*mo
 	return &responseUser, nil
 }
 
-func (s *authService) Login(ctx context.Context, email, password string) (string, string, error) {
+// Login checks the password. Failures are counted against both the account and
+// the client IP, and either one backing off or locked out fails with a *ThrottledError.
+// Users with 2FA on get an MFA token instead of a session.
+func (s *authService) Login(ctx context.Context, email, password, clientIP string) (*LoginResult, error) {
+	// 1. Charge the attempt as a failure up front and refuse early while blocked,
+	// before spending time on bcrypt
+	keys := s.throttle.keys(email, clientIP)
+	if err := s.throttle.attempt(ctx, keys); err != nil {
+		return nil, err
+	}
+
+	// 2. Unknown emails count as failures too, so they look like wrong passwords
 	user, err := s.userRepo.GetUserByEmail(ctx, email)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
-	if user == nil {
-		return "", "", errors.New("invalid credentials")
+	if user == nil || utils.CheckPassword(password, user.Password) != nil {
+		return nil, ErrInvalidCredentials
 	}
 
-	if err := utils.CheckPassword(password, user.Password); err != nil {
-		return "", "", errors.New("invalid credentials")
+	if user.IsDisabled() {
+		if err := s.throttle.refund(ctx, keys); err != nil {
+			return nil, err
+		}
+		return nil, ErrUserDisabled
 	}
 
-	accessToken, err := utils.GenerateAccessToken(user.ID, user.Email)
+	// 3. The password alone is not enough; VerifyMFA takes it from here. The earlier
+	// failures stay, or logging in again would wipe out failed codes.
+	if user.HasTwoFactor() {
+		if err := s.throttle.refund(ctx, keys); err != nil {
+			return nil, err
+		}
+		mfaToken, err := s.jwt.GenerateMFAChallengeToken(user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("generating mfa token: %w", err)
+		}
+		return &LoginResult{MFAToken: mfaToken}, nil
+	}
+	if err := s.throttle.succeed(ctx, keys); err != nil {
+		return nil, err
+	}
+
+	accessToken, refreshToken, err := s.startSession(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResult{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// startSession issues the tokens of a fresh login. Login and VerifyMFA both end here.
+func (s *authService) startSession(ctx context.Context, user *models.User) (string, string, error) {
+	accessToken, err := s.generateAccessToken(user)
 	if err != nil {
 		return "", "", fmt.Errorf("generating access token: %w", err)
 	}
 
-	rawRefreshToken, tokenHash, err := utils.GenerateRefreshToken()
+	// Every login starts a new token family
+	familyID, err := utils.GenerateTokenFamilyID()
+	if err != nil {
+		return "", "", fmt.Errorf("generating token family: %w", err)
+	}
+
+	rawRefreshToken, err := s.issueRefreshToken(ctx, user.ID, familyID)
 	if err != nil {
-		return "", "", fmt.Errorf("generating refresh token: %w", err)
+		return "", "", err
 	}
 
-	refreshTokenModel := &models.RefreshToken{
+	return accessToken, rawRefreshToken, nil
+}
+
+// ForgotPassword emails a reset link, at most one per passwordResetInterval.
+// Unknown and disabled accounts, and requests inside the interval, get nothing
+// without an error, so the result never reveals who has an account.
+func (s *authService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return fmt.Errorf("looking up user: %w", err)
+	}
+	if user == nil || user.IsDisabled() {
+		return nil
+	}
+
+	// Reserving first means a burst of requests can't flood the inbox
+	reserved, err := s.userRepo.ReservePasswordResetEmail(ctx, user.ID, passwordResetInterval)
+	if err != nil {
+		return fmt.Errorf("reserving reset email: %w", err)
+	}
+	if !reserved {
+		return nil
+	}
+
+	rawToken, tokenHash, err := utils.GenerateSecureToken()
+	if err != nil {
+		return fmt.Errorf("generating reset token: %w", err)
+	}
+	resetToken := &models.PasswordResetToken{
 		UserID:    user.ID,
 		TokenHash: tokenHash,
-		ExpiresAt: time.Now().Add(7 * 24 * time.Hour),
+		ExpiresAt: time.Now().Add(passwordResetTTL),
+	}
+	if err := s.tokenRepo.CreatePasswordResetToken(ctx, resetToken); err != nil {
+		return fmt.Errorf("saving reset token: %w", err)
 	}
 
-	if err := s.tokenRepo.CreateRefreshToken(ctx, refreshTokenModel); err != nil {
-		return "", "", fmt.Errorf("saving refresh token: %w", err)
+	link := s.appURL + "/reset-password?token=" + url.QueryEscape(rawToken)
+	err = s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your Pokedex password",
+		Body: "Someone asked to reset the password of your Pokedex account.\n\n" +
+			"Open this link within an hour to choose a new one:\n" + link + "\n\n" +
+			"If it wasn't you, ignore this email and your password stays the same.\n",
+	})
+	if err != nil {
+		return fmt.Errorf("sending reset email: %w", err)
 	}
+	return nil
+}
 
-	return accessToken, rawRefreshToken, nil
+// ResetPassword sets a new password using an emailed token. The token works once,
+// and every session of the user is signed out.
+func (s *authService) ResetPassword(ctx context.Context, rawResetToken, newPassword string) error {
+	hashedPwd, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return fmt.Errorf("hashing password: %w", err)
+	}
+
+	reset, err := s.tokenRepo.ResetPassword(ctx, utils.HashToken(rawResetToken), hashedPwd)
+	if err != nil {
+		return fmt.Errorf("resetting password: %w", err)
+	}
+	if !reset {
+		return ErrInvalidResetToken
+	}
+	return nil
+}
+
+// SendVerificationEmail emails a link that confirms the user's address. Register
+// and resend both go through here, and it sends at most one per verificationResendInterval.
+func (s *authService) SendVerificationEmail(ctx context.Context, userID int) error {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("loading user: %w", err)
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	if user.IsVerified() {
+		return ErrAlreadyVerified
+	}
+
+	// Reserving first means two concurrent resends can't both send
+	reserved, err := s.userRepo.ReserveVerificationEmail(ctx, user.ID, verificationResendInterval)
+	if err != nil {
+		return fmt.Errorf("reserving verification email: %w", err)
+	}
+	if !reserved {
+		retryAfter := verificationResendInterval
+		if user.VerificationSentAt != nil {
+			retryAfter = time.Until(user.VerificationSentAt.Add(verificationResendInterval))
+		}
+		return &ThrottledError{RetryAfter: max(retryAfter, time.Second)}
+	}
+
+	token, err := s.jwt.GenerateVerificationToken(user.ID, user.Email)
+	if err != nil {
+		return fmt.Errorf("generating verification token: %w", err)
+	}
+	link := s.appURL + "/verify-email?token=" + url.QueryEscape(token)
+	err = s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Confirm your Pokedex email address",
+		Body: "Welcome to the Pokedex!\n\n" +
+			"Open this link within a day to confirm your email address:\n" + link + "\n\n" +
+			"Until then you can browse your Pokedex but not change it.\n",
+	})
+	if err != nil {
+		return fmt.Errorf("sending verification email: %w", err)
+	}
+	return nil
+}
+
+// VerifyEmail confirms the address named in a verification token. Using a link
+// again after it worked is harmless.
+func (s *authService) VerifyEmail(ctx context.Context, verificationToken string) error {
+	claims, err := s.jwt.ValidateToken(verificationToken, utils.TokenTypeVerifyEmail)
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+	userID, okID := claims["sub"].(float64)
+	email, okEmail := claims["email"].(string)
+	if !okID || !okEmail {
+		return ErrInvalidVerificationToken
+	}
+
+	verified, err := s.userRepo.MarkEmailVerified(ctx, int(userID), email)
+	if err != nil {
+		return fmt.Errorf("marking email verified: %w", err)
+	}
+	if !verified {
+		return ErrInvalidVerificationToken
+	}
+	return nil
 }
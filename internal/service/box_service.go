@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/repository"
+)
+
+var (
+	ErrBoxNotFound = errors.New("box not found")
+	// ErrBoxSlotConflict means the target box is full or the slot is taken.
+	ErrBoxSlotConflict = errors.New("box slot conflict")
+)
+
+// maxBoxNameLength matches the name column size
+const maxBoxNameLength = 50
+
+// MoveInput places a Pokemon. A zero BoxID takes it out of its box;
+// a zero Slot picks the first free slot.
+type MoveInput struct {
+	BoxID int
+	Slot  int
+}
+
+type BoxService interface {
+	Create(ctx context.Context, userId int, name string) (*models.Box, error)
+	List(ctx context.Context, userId int) ([]models.Box, error)
+	Get(ctx context.Context, userId, id int) (*models.Box, error)
+	Rename(ctx context.Context, userId, id int, name string) (*models.Box, error)
+	Delete(ctx context.Context, userId, id int) error
+	Move(ctx context.Context, userId, pokemonId int, input MoveInput) (*models.Pokemon, error)
+	Swap(ctx context.Context, userId, pokemonId, otherId int) ([]models.Pokemon, error)
+}
+
+type boxService struct {
+	boxRepo     repository.BoxRepository
+	pokemonRepo repository.PokemonRepository
+	speciesRepo repository.SpeciesRepository
+}
+
+func NewBoxService(boxRepo repository.BoxRepository, pokemonRepo repository.PokemonRepository, speciesRepo repository.SpeciesRepository) BoxService {
+	return &boxService{
+		boxRepo:     boxRepo,
+		pokemonRepo: pokemonRepo,
+		speciesRepo: speciesRepo,
+	}
+}
+
+func validateBoxName(name string) (string, error) {
+	clean := strings.TrimSpace(name)
+	if clean == "" {
+		return "", fmt.Errorf("%w: box name cannot be empty", ErrInvalidInput)
+	}
+	if len([]rune(clean)) > maxBoxNameLength {
+		return "", fmt.Errorf("%w: box name cannot be longer than %d characters", ErrInvalidInput, maxBoxNameLength)
+	}
+	return clean, nil
+}
+
+func (s *boxService) Create(ctx context.Context, userId int, name string) (*models.Box, error) {
+	cleanName, err := validateBoxName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	box := &models.Box{UserID: userId, Name: cleanName}
+	if err := s.boxRepo.CreateBox(ctx, box); err != nil {
+		return nil, fmt.Errorf("failed to save box: %w", err)
+	}
+	return box, nil
+}
+
+func (s *boxService) List(ctx context.Context, userId int) ([]models.Box, error) {
+	boxes, err := s.boxRepo.ListBoxes(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list boxes: %w", err)
+	}
+	if boxes == nil {
+		return []models.Box{}, nil
+	}
+	return boxes, nil
+}
+
+func (s *boxService) Get(ctx context.Context, userId, id int) (*models.Box, error) {
+	box, err := s.boxRepo.GetBox(ctx, userId, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch box: %w", err)
+	}
+	if box == nil {
+		return nil, ErrBoxNotFound
+	}
+	if err := attachStats(ctx, s.speciesRepo, box.Pokemon); err != nil {
+		return nil, err
+	}
+	return box, nil
+}
+
+func (s *boxService) Rename(ctx context.Context, userId, id int, name string) (*models.Box, error) {
+	cleanName, err := validateBoxName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	box := &models.Box{ID: id, UserID: userId, Name: cleanName}
+	renamed, err := s.boxRepo.RenameBox(ctx, box)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rename box: %w", err)
+	}
+	if !renamed {
+		return nil, ErrBoxNotFound
+	}
+	return box, nil
+}
+
+// Delete removes the box; the Pokemon in it stay in the collection, unboxed.
+func (s *boxService) Delete(ctx context.Context, userId, id int) error {
+	deleted, err := s.boxRepo.DeleteBox(ctx, userId, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete box: %w", err)
+	}
+	if !deleted {
+		return ErrBoxNotFound
+	}
+	return nil
+}
+
+func (s *boxService) Move(ctx context.Context, userId, pokemonId int, input MoveInput) (*models.Pokemon, error) {
+	if input.BoxID < 0 {
+		return nil, fmt.Errorf("%w: box_id cannot be negative", ErrInvalidInput)
+	}
+	if input.Slot < 0 || input.Slot > models.MaxBoxSlots {
+		return nil, fmt.Errorf("%w: slot must be between 1 and %d", ErrInvalidInput, models.MaxBoxSlots)
+	}
+	if input.BoxID == 0 && input.Slot != 0 {
+		return nil, fmt.Errorf("%w: a slot needs a box_id", ErrInvalidInput)
+	}
+
+	moved, err := s.boxRepo.MovePokemon(ctx, userId, pokemonId, input.BoxID, input.Slot)
+	switch {
+	case errors.Is(err, repository.ErrBoxNotOwned):
+		return nil, ErrBoxNotFound
+	case errors.Is(err, repository.ErrBoxFull), errors.Is(err, repository.ErrSlotTaken):
+		return nil, fmt.Errorf("%w: %v", ErrBoxSlotConflict, err)
+	case err != nil:
+		return nil, fmt.Errorf("failed to move pokemon: %w", err)
+	case !moved:
+		return nil, ErrPokemonNotFound
+	}
+
+	pokemons, err := s.reload(ctx, userId, pokemonId)
+	if err != nil {
+		return nil, err
+	}
+	return &pokemons[0], nil
+}
+
+// Swap exchanges the places of two Pokemon and returns both, in the order given.
+func (s *boxService) Swap(ctx context.Context, userId, pokemonId, otherId int) ([]models.Pokemon, error) {
+	if otherId <= 0 {
+		return nil, fmt.Errorf("%w: pokemon_id must be positive", ErrInvalidInput)
+	}
+	if otherId == pokemonId {
+		return nil, fmt.Errorf("%w: cannot swap a pokemon with itself", ErrInvalidInput)
+	}
+
+	swapped, err := s.boxRepo.SwapPokemon(ctx, userId, pokemonId, otherId)
+	switch {
+	case errors.Is(err, repository.ErrSlotTaken):
+		return nil, fmt.Errorf("%w: %v", ErrBoxSlotConflict, err)
+	case err != nil:
+		return nil, fmt.Errorf("failed to swap pokemon: %w", err)
+	case !swapped:
+		return nil, ErrPokemonNotFound
+	}
+	return s.reload(ctx, userId, pokemonId, otherId)
+}
+
+// reload fetches the Pokemon after a move, with stats
+func (s *boxService) reload(ctx context.Context, userId int, ids ...int) ([]models.Pokemon, error) {
+	pokemons := make([]models.Pokemon, 0, len(ids))
+	for _, id := range ids {
+		pokemon, err := s.pokemonRepo.GetPokemonByID(ctx, userId, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch pokemon: %w", err)
+		}
+		if pokemon == nil {
+			return nil, ErrPokemonNotFound
+		}
+		pokemons = append(pokemons, *pokemon)
+	}
+	if err := attachStats(ctx, s.speciesRepo, pokemons); err != nil {
+		return nil, err
+	}
+	return pokemons, nil
+}
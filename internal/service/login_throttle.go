@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/repository"
+)
+
+// LoginPolicy sets how failed logins slow down the next ones. Failures are
+// counted per account and per client IP; the IP limit is usually higher,
+// since many users can share one address.
+type LoginPolicy struct {
+	FreeAttempts    int           // Failures allowed before any delay
+	BaseDelay       time.Duration // Wait after the first delayed failure, doubling with each one after
+	MaxDelay        time.Duration
+	AccountLockout  int // Failures that lock an account
+	IPLockout       int // Failures that lock out a client IP
+	LockoutDuration time.Duration
+}
+
+// loginKey is one counter a login attempt is charged to
+type loginKey struct {
+	key     string
+	lockout int
+}
+
+// loginThrottle applies a LoginPolicy to the counts in a LoginAttemptStore
+type loginThrottle struct {
+	store  repository.LoginAttemptStore
+	policy LoginPolicy
+}
+
+func (t *loginThrottle) keys(email, clientIP string) []loginKey {
+	keys := []loginKey{{"email:" + strings.ToLower(strings.TrimSpace(email)), t.policy.AccountLockout}}
+	if clientIP != "" {
+		keys = append(keys, loginKey{"ip:" + clientIP, t.policy.IPLockout})
+	}
+	return keys
+}
+
+// wait is how long after the last of failures a key stays blocked
+func (t *loginThrottle) wait(failures, lockout int) time.Duration {
+	switch {
+	case lockout > 0 && failures >= lockout:
+		return t.policy.LockoutDuration
+	case failures < t.policy.FreeAttempts:
+		return 0
+	}
+	// Stop doubling well before the shift overflows
+	delay := t.policy.MaxDelay
+	if n := failures - t.policy.FreeAttempts; n < 30 {
+		delay = min(t.policy.BaseDelay<<n, t.policy.MaxDelay)
+	}
+	return delay
+}
+
+// blocked reports whether the failures before a were still waiting it out when it was recorded
+func (t *loginThrottle) blocked(a *models.LoginAttempts, lockout int) bool {
+	if a.PreviousFailureAt == nil {
+		return false
+	}
+	return a.LastFailureAt.Sub(*a.PreviousFailureAt) < t.wait(a.Failures-1, lockout)
+}
+
+// attempt charges an attempt to every key as a failure before its outcome is
+// known, so a burst of parallel guesses can't all pass one check. It returns a
+// *ThrottledError naming the longest wait if any key was blocked; the charge
+// stays, so hammering a blocked key only extends the wait.
+func (t *loginThrottle) attempt(ctx context.Context, keys []loginKey) error {
+	var retryAfter time.Duration
+	for _, k := range keys {
+		attempts, err := t.store.RecordLoginFailure(ctx, k.key)
+		if err != nil {
+			return fmt.Errorf("recording login attempt: %w", err)
+		}
+		if t.blocked(attempts, k.lockout) {
+			wait := time.Until(attempts.LastFailureAt.Add(t.wait(attempts.Failures, k.lockout)))
+			retryAfter = max(retryAfter, wait, time.Second)
+		}
+	}
+	if retryAfter > 0 {
+		return &ThrottledError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// refund takes back the charge of an attempt that turned out not to be a failure
+func (t *loginThrottle) refund(ctx context.Context, keys []loginKey) error {
+	for _, k := range keys {
+		if err := t.store.RefundLoginFailure(ctx, k.key); err != nil {
+			return fmt.Errorf("refunding login attempt: %w", err)
+		}
+	}
+	return nil
+}
+
+// succeed clears the account's count. The IP only gets this attempt back, or
+// an attacker could wipe its count by logging in to an account of their own.
+func (t *loginThrottle) succeed(ctx context.Context, keys []loginKey) error {
+	if err := t.store.ResetLoginAttempts(ctx, keys[0].key); err != nil {
+		return fmt.Errorf("resetting login attempts: %w", err)
+	}
+	return t.refund(ctx, keys[1:])
+}
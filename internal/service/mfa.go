@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/utils"
+)
+
+// totpIssuer names the account in authenticator apps
+const totpIssuer = "Pokedex"
+
+// recoveryCodeCount is how many recovery codes a user gets when enabling 2FA
+const recoveryCodeCount = 10
+
+var (
+	// ErrInvalidMFAToken covers unknown and expired challenge tokens alike.
+	ErrInvalidMFAToken   = errors.New("invalid or expired mfa token")
+	ErrInvalidMFACode    = errors.New("invalid two-factor code")
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication already enabled")
+	ErrMFANotEnabled     = errors.New("two-factor authentication not enabled")
+	// ErrMFANotEnrolled means confirm was called without enrolling first.
+	ErrMFANotEnrolled = errors.New("two-factor enrollment not started")
+)
+
+// LoginResult is what a correct password earns: a session, or when the user has
+// 2FA on, only an MFAToken to complete with VerifyMFA.
+type LoginResult struct {
+	AccessToken  string
+	RefreshToken string
+	MFAToken     string
+}
+
+// MFARequired reports whether the login still needs a second factor.
+func (r *LoginResult) MFARequired() bool {
+	return r.MFAToken != ""
+}
+
+// TOTPEnrollment is the secret to load into an authenticator app. The URI holds
+// the same secret, ready for a QR code.
+type TOTPEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+// VerifyMFA completes a login that Login answered with an MFA token. code is a
+// TOTP code or an unused recovery code. Wrong codes count as failed logins.
+func (s *authService) VerifyMFA(ctx context.Context, mfaToken, code, clientIP string) (string, string, error) {
+	// 1. The challenge says whose password was already checked
+	claims, err := s.jwt.ValidateToken(mfaToken, utils.TokenTypeMFAChallenge)
+	if err != nil {
+		return "", "", ErrInvalidMFAToken
+	}
+	userID, ok := claims["sub"].(float64)
+	if !ok {
+		return "", "", ErrInvalidMFAToken
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, int(userID))
+	if err != nil {
+		return "", "", fmt.Errorf("loading user: %w", err)
+	}
+	if user == nil || !user.HasTwoFactor() {
+		return "", "", ErrInvalidMFAToken
+	}
+	if user.IsDisabled() {
+		return "", "", ErrUserDisabled
+	}
+
+	// 2. Six digits are easy to guess, so they share the login's throttle
+	keys := s.throttle.keys(user.Email, clientIP)
+	if err := s.throttle.attempt(ctx, keys); err != nil {
+		return "", "", err
+	}
+	valid, err := s.checkSecondFactor(ctx, user, code)
+	if err != nil {
+		return "", "", err
+	}
+	if !valid {
+		return "", "", ErrInvalidMFACode
+	}
+	if err := s.throttle.succeed(ctx, keys); err != nil {
+		return "", "", err
+	}
+
+	// 3. Only now does the login become a session
+	return s.startSession(ctx, user)
+}
+
+// EnrollTOTP generates a new secret for the user. 2FA stays off until
+// ConfirmTOTP proves the authenticator app has it; enrolling again replaces it.
+// Like every 2FA change it takes the password, so a stolen access token alone can't do it.
+func (s *authService) EnrollTOTP(ctx context.Context, userID int, password, clientIP string) (*TOTPEnrollment, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("loading user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if user.HasTwoFactor() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if err := s.reauthenticate(ctx, user, password, clientIP); err != nil {
+		return nil, err
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, fmt.Errorf("generating totp secret: %w", err)
+	}
+	saved, err := s.mfaRepo.SetPendingTOTPSecret(ctx, user.ID, secret)
+	if err != nil {
+		return nil, fmt.Errorf("saving totp secret: %w", err)
+	}
+	if !saved {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	return &TOTPEnrollment{
+		Secret: secret,
+		URI:    utils.TOTPURI(totpIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTP turns 2FA on once the user shows their password and a code from
+// the enrolled secret. It returns the recovery codes, which are only stored
+// hashed and never shown again.
+func (s *authService) ConfirmTOTP(ctx context.Context, userID int, password, code, clientIP string) ([]string, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("loading user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	if user.HasTwoFactor() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, ErrMFANotEnrolled
+	}
+	if err := s.reauthenticate(ctx, user, password, clientIP); err != nil {
+		return nil, err
+	}
+
+	step, ok := utils.ValidateTOTP(user.TOTPSecret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		if codes[i], err = utils.GenerateRecoveryCode(); err != nil {
+			return nil, fmt.Errorf("generating recovery code: %w", err)
+		}
+		hashes[i] = utils.HashRecoveryCode(codes[i])
+	}
+
+	// A concurrent enroll may have swapped the secret since we checked the code
+	enabled, err := s.mfaRepo.EnableTOTP(ctx, user.ID, user.TOTPSecret, step, hashes)
+	if err != nil {
+		return nil, fmt.Errorf("enabling totp: %w", err)
+	}
+	if !enabled {
+		return nil, ErrInvalidMFACode
+	}
+	return codes, nil
+}
+
+// DisableTOTP turns 2FA off. A stolen access token alone isn't enough: it takes
+// the password and a current code or recovery code, and failures are throttled like logins.
+func (s *authService) DisableTOTP(ctx context.Context, userID int, password, code, clientIP string) error {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("loading user: %w", err)
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	if !user.HasTwoFactor() {
+		return ErrMFANotEnabled
+	}
+
+	keys := s.throttle.keys(user.Email, clientIP)
+	if err := s.throttle.attempt(ctx, keys); err != nil {
+		return err
+	}
+	if utils.CheckPassword(password, user.Password) != nil {
+		return ErrInvalidCredentials
+	}
+	valid, err := s.checkSecondFactor(ctx, user, code)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return ErrInvalidMFACode
+	}
+	if err := s.throttle.succeed(ctx, keys); err != nil {
+		return err
+	}
+
+	if err := s.mfaRepo.DisableTOTP(ctx, user.ID); err != nil {
+		return fmt.Errorf("disabling totp: %w", err)
+	}
+	return nil
+}
+
+// reauthenticate checks the password of a signed-in user before a security
+// change. Wrong passwords are throttled like failed logins.
+func (s *authService) reauthenticate(ctx context.Context, user *models.User, password, clientIP string) error {
+	keys := s.throttle.keys(user.Email, clientIP)
+	if err := s.throttle.attempt(ctx, keys); err != nil {
+		return err
+	}
+	if utils.CheckPassword(password, user.Password) != nil {
+		return ErrInvalidCredentials
+	}
+	return s.throttle.succeed(ctx, keys)
+}
+
+// checkSecondFactor accepts a TOTP code or a recovery code and spends it,
+// so neither works twice.
+func (s *authService) checkSecondFactor(ctx context.Context, user *models.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if isTOTPCode(code) {
+		step, ok := utils.ValidateTOTP(user.TOTPSecret, code, time.Now())
+		if !ok {
+			return false, nil
+		}
+		used, err := s.mfaRepo.UseTOTPStep(ctx, user.ID, step)
+		if err != nil {
+			return false, fmt.Errorf("recording totp use: %w", err)
+		}
+		return used, nil
+	}
+
+	used, err := s.mfaRepo.UseRecoveryCode(ctx, user.ID, utils.HashRecoveryCode(code))
+	if err != nil {
+		return false, fmt.Errorf("spending recovery code: %w", err)
+	}
+	return used, nil
+}
+
+// isTOTPCode tells authenticator codes (six digits) from recovery codes
+func isTOTPCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
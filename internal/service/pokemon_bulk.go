@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/bulk"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+)
+
+// maxImportRows caps a single import, since atomic mode holds every row until commit
+const maxImportRows = 10_000
+
+// ImportMode decides what happens to the good rows when some rows fail.
+type ImportMode string
+
+const (
+	// ImportAtomic saves nothing unless every row is valid
+	ImportAtomic ImportMode = "atomic"
+	// ImportBestEffort saves every valid row and reports the rest
+	ImportBestEffort ImportMode = "best_effort"
+)
+
+// ImportRowError explains why one row was rejected. Rows are numbered from 1, excluding the CSV header.
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
+
+// ImportReport summarizes an import.
+type ImportReport struct {
+	Mode     ImportMode       `json:"mode"`
+	Imported int              `json:"imported"`
+	Failed   int              `json:"failed"`
+	Errors   []ImportRowError `json:"errors"`
+}
+
+// Import validates every row with the same rules as Create. In atomic mode the
+// report lists every failure and nothing is saved unless all rows pass.
+func (p *pokemonService) Import(ctx context.Context, userId int, rows bulk.Reader, mode ImportMode) (*ImportReport, error) {
+	if mode != ImportAtomic && mode != ImportBestEffort {
+		return nil, fmt.Errorf("%w: unknown import mode %q", ErrInvalidInput, mode)
+	}
+
+	report := &ImportReport{Mode: mode, Errors: []ImportRowError{}}
+	species := make(map[int]*models.Species) // Collections repeat species a lot
+	var pending []*models.Pokemon
+
+	for row := 1; ; row++ {
+		// 1. Decode the next row; a broken row doesn't stop the import
+		record, err := rows.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if row > maxImportRows {
+			return nil, fmt.Errorf("%w: an import holds at most %d rows", ErrInvalidInput, maxImportRows)
+		}
+		var rowErr *bulk.RowError
+		if errors.As(err, &rowErr) {
+			report.fail(rowErr.Row, rowErr.Err.Error())
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+
+		// 2. Validate it like Create would
+		s, ok := species[record.PokedexID]
+		if !ok {
+			if s, err = p.speciesRepo.GetSpeciesByID(ctx, record.PokedexID); err != nil {
+				return nil, fmt.Errorf("failed to look up species: %w", err)
+			}
+			species[record.PokedexID] = s
+		}
+		if s == nil {
+			report.fail(row, fmt.Sprintf("unknown pokedex_id %d", record.PokedexID))
+			continue
+		}
+
+		var caughtAt *time.Time
+		if !record.CaughtAt.IsZero() {
+			caughtAt = &record.CaughtAt
+		}
+		pokemon, err := buildPokemon(userId, s, CreatePokemonInput{
+			PokedexID:  record.PokedexID,
+			Nickname:   record.Nickname,
+			Level:      record.Level,
+			Nature:     record.Nature,
+			Ability:    record.Ability,
+			IVs:        record.IVs,
+			EVs:        record.EVs,
+			HeldItem:   record.HeldItem,
+			Moves:      record.Moves,
+			Friendship: record.Friendship,
+			Tags:       record.Tags,
+
+			Shiny:           record.Shiny,
+			Gender:          string(record.Gender),
+			Form:            record.Form,
+			Ball:            record.Ball,
+			OriginalTrainer: record.OriginalTrainer,
+			CaughtAt:        caughtAt,
+			CaughtLocation:  record.CaughtLocation,
+		})
+		if err != nil {
+			report.fail(row, inputErrorMessage(err))
+			continue
+		}
+
+		// 3. Best effort saves as it goes; atomic waits for the verdict on every row
+		if mode == ImportBestEffort {
+			if err := p.pokemonRepo.CreatePokemon(ctx, pokemon); err != nil {
+				return nil, fmt.Errorf("failed to save pokemon: %w", err)
+			}
+			report.Imported++
+			continue
+		}
+		pending = append(pending, pokemon)
+	}
+
+	if mode == ImportAtomic && report.Failed == 0 && len(pending) > 0 {
+		if err := p.pokemonRepo.CreatePokemons(ctx, pending); err != nil {
+			return nil, fmt.Errorf("failed to save pokemon: %w", err)
+		}
+		report.Imported = len(pending)
+	}
+	return report, nil
+}
+
+func (r *ImportReport) fail(row int, message string) {
+	r.Failed++
+	r.Errors = append(r.Errors, ImportRowError{Row: row, Message: message})
+}
+
+// Export streams the user's whole collection into w, oldest first.
+func (p *pokemonService) Export(ctx context.Context, userId int, w bulk.Writer) error {
+	err := p.pokemonRepo.StreamPokemonByUserID(ctx, userId, func(pokemon *models.Pokemon) error {
+		return w.Write(pokemon)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export pokemon: %w", err)
+	}
+	return w.Flush()
+}
@@ -4,83 +4,1068 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand/v2"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/sanskarchoudhry/pokedex-backend/internal/bulk"
 	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
 	"github.com/sanskarchoudhry/pokedex-backend/internal/repository"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/showdown"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/stats"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/typechart"
 )
 
 // Define custom errors so the Handler knows what status code to send
 // (e.g., ErrInvalidInput -> 400 Bad Request)
 var (
-	ErrInvalidInput = errors.New("invalid input data")
+	ErrInvalidInput    = errors.New("invalid input data")
+	ErrPokemonNotFound = errors.New("pokemon not found")
 )
 
+// maxNicknameLength matches the nickname column size
+const maxNicknameLength = 100
+
+// maxIdentifierLength matches the held_item column size and bounds move names
+const maxIdentifierLength = 50
+
+// maxTagLength keeps tags short enough to show as labels
+const maxTagLength = 30
+
+// maxTrainerNameLength and maxLocationLength match their column sizes
+const (
+	maxTrainerNameLength = 50
+	maxLocationLength    = 100
+)
+
+// maxPasteLength and maxPasteSets bound a Showdown import, which resolves every
+// set against the catalog before saving. 100 sets is a few boxes' worth.
+const (
+	maxPasteLength = 64 << 10
+	maxPasteSets   = 100
+)
+
+const (
+	// defaultLevel is the level starters are received at
+	defaultLevel = 5
+	// defaultFriendship is what most species start with when caught
+	defaultFriendship = 70
+	// defaultBall is the standard Poke Ball
+	defaultBall = "poke-ball"
+)
+
+// CreatePokemonInput describes a newly caught Pokemon. Zero values get defaults:
+// level 5, a neutral nature, the species' first ability, 70 friendship, a Poke Ball,
+// caught now, and a gender rolled from the species' gender ratio.
+type CreatePokemonInput struct {
+	PokedexID  int
+	Nickname   string
+	Level      int
+	Nature     string
+	Ability    string
+	IVs        models.StatSpread
+	EVs        models.StatSpread
+	HeldItem   string
+	Moves      []string
+	Friendship int
+	Tags       []string
+
+	Shiny           bool
+	Gender          string
+	Form            string
+	Ball            string
+	OriginalTrainer string
+	CaughtAt        *time.Time
+	CaughtLocation  string
+}
+
+// UpdatePokemonInput holds a partial update. Nil fields are left unchanged;
+// a non-nil IVs or EVs replaces the whole spread.
+// Species data (name, type, measurements) comes from the catalog and can't be edited.
+type UpdatePokemonInput struct {
+	Nickname   *string
+	Level      *int
+	Nature     *string
+	Ability    *string
+	IVs        *models.StatSpread
+	EVs        *models.StatSpread
+	HeldItem   *string
+	Moves      *[]string
+	Friendship *int
+	Tags       *[]string // Replaces every tag
+
+	Shiny           *bool
+	Gender          *string
+	Form            *string
+	Ball            *string
+	OriginalTrainer *string
+	CaughtAt        *time.Time
+	CaughtLocation  *string
+}
+
+// EvolveInput picks the evolution. IntoSpeciesID is only needed when several
+// are possible; Item is the item used for use-item evolutions such as a Fire Stone.
+type EvolveInput struct {
+	IntoSpeciesID int
+	Item          string
+}
+
 type PokemonService interface {
-	Create(ctx context.Context, userId int, pokedexId int, name, nickname, pokemonType string, height, weight int) (*models.Pokemon, error)
-	List(ctx context.Context, userId int) ([]models.Pokemon, error)
+	Create(ctx context.Context, userId int, input CreatePokemonInput) (*models.Pokemon, error)
+	List(ctx context.Context, userId int, opts models.PokemonListOptions) (*models.PokemonPage, error)
+	Progress(ctx context.Context, userId int, opts models.DexProgressOptions) (*models.DexProgress, error)
+	Get(ctx context.Context, userId, id int) (*models.Pokemon, error)
+	Update(ctx context.Context, userId, id int, input UpdatePokemonInput) (*models.Pokemon, error)
+	Delete(ctx context.Context, userId, id int) error
+	Weaknesses(ctx context.Context, userId, id int) (*typechart.DefenseProfile, error)
+	Evolve(ctx context.Context, userId, id int, input EvolveInput) (*models.Pokemon, error)
+	History(ctx context.Context, userId, id int) ([]models.PokemonEvent, error)
+	Activity(ctx context.Context, userId int, opts models.ActivityOptions) (*models.ActivityPage, error)
+	ImportShowdown(ctx context.Context, userId int, paste string) ([]models.Pokemon, error)
+	Import(ctx context.Context, userId int, rows bulk.Reader, mode ImportMode) (*ImportReport, error)
+	Export(ctx context.Context, userId int, w bulk.Writer) error
 }
 
 type pokemonService struct {
 	pokemonRepo repository.PokemonRepository
+	speciesRepo repository.SpeciesRepository
+	eventRepo   repository.EventRepository
 }
 
-func NewPokemonService(repo repository.PokemonRepository) PokemonService {
+func NewPokemonService(repo repository.PokemonRepository, speciesRepo repository.SpeciesRepository, eventRepo repository.EventRepository) PokemonService {
 	return &pokemonService{
 		pokemonRepo: repo,
+		speciesRepo: speciesRepo,
+		eventRepo:   eventRepo,
+	}
+}
+
+// validatePokemon enforces the rules shared by Create and Update
+func validatePokemon(p *models.Pokemon) error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("%w: name cannot be empty", ErrInvalidInput)
+	}
+	if p.PokedexID <= 0 {
+		return fmt.Errorf("%w: pokedex_id must be positive", ErrInvalidInput)
+	}
+	if err := validateTypes(p.Types); err != nil {
+		return err
+	}
+	if p.Height <= 0 || p.Weight <= 0 {
+		return fmt.Errorf("%w: height and weight must be positive", ErrInvalidInput)
 	}
+	if len([]rune(strings.TrimSpace(p.Nickname))) > maxNicknameLength {
+		return fmt.Errorf("%w: nickname cannot be longer than %d characters", ErrInvalidInput, maxNicknameLength)
+	}
+	if err := validateTags(p.Tags); err != nil {
+		return err
+	}
+	if err := validateCatch(p); err != nil {
+		return err
+	}
+	return validateTraining(p)
 }
 
-func (p *pokemonService) Create(ctx context.Context, userId int, pokedexId int, name, nickname, pokemonType string, height, weight int) (*models.Pokemon, error) {
+// validateCatch checks the variant and catch details that don't depend on the species
+func validateCatch(p *models.Pokemon) error {
+	if gender := parseGender(string(p.Gender)); gender != "" && !gender.IsValid() {
+		return fmt.Errorf("%w: gender must be male, female or genderless", ErrInvalidInput)
+	}
+	if len(toIdentifier(p.Form)) > maxIdentifierLength {
+		return fmt.Errorf("%w: form cannot be longer than %d characters", ErrInvalidInput, maxIdentifierLength)
+	}
+	// There is no item catalog, but every ball's identifier ends in "-ball"
+	ball := toIdentifier(p.Ball)
+	if !strings.HasSuffix(ball, "-ball") || len(ball) > maxIdentifierLength {
+		return fmt.Errorf("%w: %q is not a poke ball", ErrInvalidInput, p.Ball)
+	}
+	if len([]rune(strings.TrimSpace(p.OriginalTrainer))) > maxTrainerNameLength {
+		return fmt.Errorf("%w: original trainer cannot be longer than %d characters", ErrInvalidInput, maxTrainerNameLength)
+	}
+	if len([]rune(strings.TrimSpace(p.CaughtLocation))) > maxLocationLength {
+		return fmt.Errorf("%w: caught location cannot be longer than %d characters", ErrInvalidInput, maxLocationLength)
+	}
+	if p.CaughtAt.After(time.Now()) {
+		return fmt.Errorf("%w: caught_at cannot be in the future", ErrInvalidInput)
+	}
+	return nil
+}
 
-	if strings.TrimSpace(name) == "" {
-		return nil, fmt.Errorf("%w: name cannot be empty", ErrInvalidInput)
+// validateVariant checks gender and form against what the species allows.
+// Species imported before gender rates were tracked accept any gender.
+func validateVariant(p *models.Pokemon, species *models.Species) error {
+	gender := parseGender(string(p.Gender))
+	if genders := species.Genders(); gender != "" && genders != nil && !slices.Contains(genders, gender) {
+		if len(genders) == 1 && genders[0] == models.GenderGenderless {
+			return fmt.Errorf("%w: %s is genderless", ErrInvalidInput, species.Name)
+		}
+		return fmt.Errorf("%w: %s is always %s", ErrInvalidInput, species.Name, genders[0])
 	}
-	if pokedexId <= 0 {
-		return nil, fmt.Errorf("%w: pokedex_id must be positive", ErrInvalidInput)
+	if form := toIdentifier(p.Form); !species.HasForm(form) {
+		return fmt.Errorf("%w: %s has no %q form", ErrInvalidInput, species.Name, p.Form)
+	}
+	return nil
+}
+
+// defaultGender picks the species' only gender, or rolls one from its gender
+// ratio like the games do. It is empty if the catalog doesn't know the ratio.
+func defaultGender(species *models.Species) models.Gender {
+	genders := species.Genders()
+	switch len(genders) {
+	case 0:
+		return ""
+	case 1:
+		return genders[0]
+	}
+	if rand.IntN(models.GenderRateFemaleOnly) < *species.GenderRate {
+		return models.GenderFemale
+	}
+	return models.GenderMale
+}
+
+func parseGender(s string) models.Gender {
+	return models.Gender(strings.ToLower(strings.TrimSpace(s)))
+}
+
+// validateTags bounds the number and length of tags; their wording is up to the user
+func validateTags(tags []string) error {
+	if len(tags) > models.MaxTags {
+		return fmt.Errorf("%w: a pokemon can have at most %d tags", ErrInvalidInput, models.MaxTags)
+	}
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || len([]rune(tag)) > maxTagLength {
+			return fmt.Errorf("%w: tags must be 1 to %d characters", ErrInvalidInput, maxTagLength)
+		}
+	}
+	return nil
+}
+
+// normalizeTag makes tags case-insensitive, so "Shiny" and "shiny" are the same tag
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// validateTraining checks level, nature, IVs and EVs against the game's limits
+func validateTraining(p *models.Pokemon) error {
+	if p.Level < stats.MinLevel || p.Level > stats.MaxLevel {
+		return fmt.Errorf("%w: level must be between %d and %d", ErrInvalidInput, stats.MinLevel, stats.MaxLevel)
+	}
+	if _, err := stats.ParseNature(p.Nature); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	for _, iv := range p.IVs.Values() {
+		if iv < 0 || iv > stats.MaxIV {
+			return fmt.Errorf("%w: IVs must be between 0 and %d", ErrInvalidInput, stats.MaxIV)
+		}
+	}
+	for _, ev := range p.EVs.Values() {
+		if ev < 0 || ev > stats.MaxEV {
+			return fmt.Errorf("%w: EVs must be between 0 and %d", ErrInvalidInput, stats.MaxEV)
+		}
+	}
+	if p.EVs.Total() > stats.MaxEVTotal {
+		return fmt.Errorf("%w: EVs cannot add up to more than %d", ErrInvalidInput, stats.MaxEVTotal)
+	}
+	if p.Friendship < 0 || p.Friendship > models.MaxFriendship {
+		return fmt.Errorf("%w: friendship must be between 0 and %d", ErrInvalidInput, models.MaxFriendship)
+	}
+	return validateMoveset(p)
+}
+
+// validateMoveset checks the held item and moves. There is no item or move
+// catalog, so any name is accepted as long as it fits.
+func validateMoveset(p *models.Pokemon) error {
+	if len(toIdentifier(p.HeldItem)) > maxIdentifierLength {
+		return fmt.Errorf("%w: held item cannot be longer than %d characters", ErrInvalidInput, maxIdentifierLength)
+	}
+	if len(p.Moves) > models.MaxMoves {
+		return fmt.Errorf("%w: a pokemon knows at most %d moves", ErrInvalidInput, models.MaxMoves)
+	}
+	seen := make(map[string]bool, len(p.Moves))
+	for _, move := range p.Moves {
+		id := toIdentifier(move)
+		if id == "" || len(id) > maxIdentifierLength {
+			return fmt.Errorf("%w: move names must be 1 to %d characters", ErrInvalidInput, maxIdentifierLength)
+		}
+		if seen[id] {
+			return fmt.Errorf("%w: move %q is listed twice", ErrInvalidInput, move)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// validateAbility requires one of the species' abilities. Species imported
+// before abilities were tracked have none, so only an empty ability fits them.
+func validateAbility(p *models.Pokemon, species *models.Species) error {
+	ability := toIdentifier(p.Ability)
+	if ability == "" && len(species.Abilities) == 0 {
+		return nil
+	}
+	for _, a := range species.Abilities {
+		if a == ability {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s cannot have the ability %q", ErrInvalidInput, species.Name, p.Ability)
+}
+
+// identifierReplacer strips the punctuation PokeAPI identifiers leave out
+var identifierReplacer = strings.NewReplacer(
+	" ", "-", ".", "", "'", "", "’", "", ":", "", "é", "e", "♀", "-f", "♂", "-m",
+)
+
+// toIdentifier turns a display name into a PokeAPI identifier:
+// "Sand Veil" -> "sand-veil", "Mr. Mime" -> "mr-mime", "Farfetch’d" -> "farfetchd".
+func toIdentifier(s string) string {
+	return identifierReplacer.Replace(strings.ToLower(strings.TrimSpace(s)))
+}
+
+// validateTypes requires a known primary type and, if present, a different known secondary type
+func validateTypes(t models.Types) error {
+	if !t.Primary.IsValid() {
+		return fmt.Errorf("%w: unknown primary type %q", ErrInvalidInput, t.Primary)
+	}
+	if t.Secondary == "" {
+		return nil
 	}
-	if height <= 0 || weight <= 0 {
-		return nil, fmt.Errorf("%w: height and weight must be positive", ErrInvalidInput)
+	if !t.Secondary.IsValid() {
+		return fmt.Errorf("%w: unknown secondary type %q", ErrInvalidInput, t.Secondary)
 	}
+	if t.Secondary == t.Primary {
+		return fmt.Errorf("%w: secondary type must differ from primary type", ErrInvalidInput)
+	}
+	return nil
+}
 
-	// Normalize inputs (e.g., trim whitespace)
-	cleanName := strings.TrimSpace(name)
-	cleanNickname := strings.TrimSpace(nickname)
+// normalizePokemon trims user input and applies defaults
+func normalizePokemon(p *models.Pokemon) {
+	p.Name = strings.TrimSpace(p.Name)
+	p.Nickname = strings.TrimSpace(p.Nickname)
+	p.Nature, _ = stats.ParseNature(p.Nature)
+	p.Ability = toIdentifier(p.Ability)
+	p.HeldItem = toIdentifier(p.HeldItem)
+	moves := make([]string, len(p.Moves))
+	for i, move := range p.Moves {
+		moves[i] = toIdentifier(move)
+	}
+	p.Moves = moves
+	tags := make([]string, 0, len(p.Tags))
+	seen := make(map[string]bool, len(p.Tags))
+	for _, tag := range p.Tags {
+		tag = normalizeTag(tag)
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	p.Tags = tags
+	p.Gender = parseGender(string(p.Gender))
+	p.Form = toIdentifier(p.Form)
+	p.Ball = toIdentifier(p.Ball)
+	p.OriginalTrainer = strings.TrimSpace(p.OriginalTrainer)
+	p.CaughtLocation = strings.TrimSpace(p.CaughtLocation)
 
 	// Rule: If nickname is empty, default to the Pokemon Name
-	if cleanNickname == "" {
-		cleanNickname = cleanName
+	if p.Nickname == "" {
+		p.Nickname = p.Name
 	}
+}
 
-	newPokemon := &models.Pokemon{
-		UserID:    userId,
-		PokedexID: pokedexId,
-		Name:      cleanName,
-		Nickname:  cleanNickname,
-		Type:      pokemonType,
-		Height:    height,
-		Weight:    weight,
+func (p *pokemonService) Create(ctx context.Context, userId int, input CreatePokemonInput) (*models.Pokemon, error) {
+	if input.PokedexID <= 0 {
+		return nil, fmt.Errorf("%w: pokedex_id must be positive", ErrInvalidInput)
+	}
+
+	// The catalog is the source of truth for everything the trainer can't choose
+	species, err := p.speciesRepo.GetSpeciesByID(ctx, input.PokedexID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up species: %w", err)
+	}
+	if species == nil {
+		return nil, fmt.Errorf("%w: unknown pokedex_id %d", ErrInvalidInput, input.PokedexID)
+	}
+
+	newPokemon, err := buildPokemon(userId, species, input)
+	if err != nil {
+		return nil, err
 	}
 
 	if err := p.pokemonRepo.CreatePokemon(ctx, newPokemon); err != nil {
 		return nil, fmt.Errorf("failed to save pokemon: %w", err)
 	}
 
+	setStats(newPokemon, species)
+	return newPokemon, nil
+}
+
+// buildPokemon applies defaults to a new catch of the given species and validates it
+func buildPokemon(userId int, species *models.Species, input CreatePokemonInput) (*models.Pokemon, error) {
+	newPokemon := &models.Pokemon{
+		UserID:     userId,
+		PokedexID:  species.ID,
+		Name:       species.Name,
+		Nickname:   input.Nickname,
+		Types:      species.Types,
+		Height:     species.Height,
+		Weight:     species.Weight,
+		Level:      input.Level,
+		Nature:     input.Nature,
+		Ability:    input.Ability,
+		IVs:        input.IVs,
+		EVs:        input.EVs,
+		HeldItem:   input.HeldItem,
+		Moves:      input.Moves,
+		Friendship: input.Friendship,
+		Tags:       input.Tags,
+
+		Shiny:           input.Shiny,
+		Gender:          models.Gender(input.Gender),
+		Form:            input.Form,
+		Ball:            input.Ball,
+		OriginalTrainer: input.OriginalTrainer,
+		CaughtLocation:  input.CaughtLocation,
+	}
+	if input.CaughtAt != nil {
+		newPokemon.CaughtAt = *input.CaughtAt
+	}
+	if newPokemon.Level == 0 {
+		newPokemon.Level = defaultLevel
+	}
+	if newPokemon.Friendship == 0 {
+		newPokemon.Friendship = defaultFriendship
+	}
+	if newPokemon.Nature == "" {
+		newPokemon.Nature = stats.DefaultNature
+	}
+	if newPokemon.Ability == "" && len(species.Abilities) > 0 {
+		newPokemon.Ability = species.Abilities[0]
+	}
+	if newPokemon.Ball == "" {
+		newPokemon.Ball = defaultBall
+	}
+	if newPokemon.Gender == "" {
+		newPokemon.Gender = defaultGender(species)
+	}
+
+	if err := validatePokemon(newPokemon); err != nil {
+		return nil, err
+	}
+	if err := validateAbility(newPokemon, species); err != nil {
+		return nil, err
+	}
+	if err := validateVariant(newPokemon, species); err != nil {
+		return nil, err
+	}
+	normalizePokemon(newPokemon)
 	return newPokemon, nil
 }
 
-func (p *pokemonService) List(ctx context.Context, userId int) ([]models.Pokemon, error) {
-	// We return a nil slice instead of an error if the user has 0 pokemon,
-	// but we must check if the DB failed.
-	list, err := p.pokemonRepo.ListPokemonByUserID(ctx, userId)
+// ImportShowdown saves every set in a Showdown paste, or none of them. Paste and
+// validation problems are reported together as a *showdown.ParseError wrapping ErrInvalidInput.
+func (p *pokemonService) ImportShowdown(ctx context.Context, userId int, paste string) ([]models.Pokemon, error) {
+	if len(paste) > maxPasteLength {
+		return nil, fmt.Errorf("%w: a paste can be at most %d KiB", ErrInvalidInput, maxPasteLength>>10)
+	}
+	sets, err := showdown.Parse(paste)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInput, err)
+	}
+	if len(sets) > maxPasteSets {
+		return nil, fmt.Errorf("%w: a paste holds at most %d Pokemon", ErrInvalidInput, maxPasteSets)
+	}
+
+	// 1. Resolve and validate every set before saving anything
+	pokemons := make([]*models.Pokemon, 0, len(sets))
+	species := make(map[int]*models.Species, len(sets))
+	var lineErrs []showdown.LineError
+	for _, set := range sets {
+		s, form, err := p.resolveSpecies(ctx, set.Pokemon.Name)
+		if err != nil {
+			return nil, err
+		}
+		if s == nil {
+			lineErrs = append(lineErrs, showdown.LineError{Line: set.Line, Message: fmt.Sprintf("unknown species %q", set.Pokemon.Name)})
+			continue
+		}
+
+		pokemon, err := buildPokemon(userId, s, CreatePokemonInput{
+			PokedexID:  s.ID,
+			Nickname:   set.Pokemon.Nickname,
+			Level:      set.Pokemon.Level,
+			Nature:     set.Pokemon.Nature,
+			Ability:    set.Pokemon.Ability,
+			IVs:        set.Pokemon.IVs,
+			EVs:        set.Pokemon.EVs,
+			HeldItem:   set.Pokemon.HeldItem,
+			Moves:      set.Pokemon.Moves,
+			Friendship: set.Pokemon.Friendship,
+			Shiny:      set.Pokemon.Shiny,
+			Gender:     string(set.Pokemon.Gender),
+			Form:       form,
+			Ball:       set.Pokemon.Ball,
+		})
+		if err != nil {
+			lineErrs = append(lineErrs, showdown.LineError{Line: set.Line, Message: inputErrorMessage(err)})
+			continue
+		}
+		pokemons = append(pokemons, pokemon)
+		species[s.ID] = s
+	}
+	if len(lineErrs) > 0 {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInput, &showdown.ParseError{Errors: lineErrs})
+	}
+
+	// 2. Save them all in one transaction
+	if err := p.pokemonRepo.CreatePokemons(ctx, pokemons); err != nil {
+		return nil, fmt.Errorf("failed to save pokemon: %w", err)
+	}
+
+	created := make([]models.Pokemon, len(pokemons))
+	for i, pokemon := range pokemons {
+		setStats(pokemon, species[pokemon.PokedexID])
+		created[i] = *pokemon
+	}
+	return created, nil
+}
+
+// inputErrorMessage drops the ErrInvalidInput prefix for reports that already say the input is invalid
+func inputErrorMessage(err error) string {
+	return strings.TrimPrefix(err.Error(), ErrInvalidInput.Error()+": ")
+}
+
+// resolveSpecies finds a species by its display name. Alternate forms such as
+// "Rotom-Wash" fall back to the base species, and the form ("wash") is returned
+// if the catalog knows it. Returns nil if nothing matches.
+func (p *pokemonService) resolveSpecies(ctx context.Context, name string) (*models.Species, string, error) {
+	full := toIdentifier(name)
+	id := full
+	for id != "" {
+		species, err := p.speciesRepo.GetSpeciesByName(ctx, id)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to look up species: %w", err)
+		}
+		if species != nil {
+			form := strings.TrimPrefix(strings.TrimPrefix(full, id), "-")
+			if !species.HasForm(form) {
+				form = ""
+			}
+			return species, form, nil
+		}
+
+		cut := strings.LastIndex(id, "-")
+		if cut < 0 {
+			break
+		}
+		id = id[:cut]
+	}
+	return nil, "", nil
+}
+
+// setStats computes the final stats from the species' base stats. Species
+// imported before base stats were tracked have none, so Stats stays nil.
+func setStats(p *models.Pokemon, species *models.Species) {
+	if species == nil || species.BaseStats.Total() == 0 {
+		return
+	}
+	computed := stats.Compute(species.BaseStats, p.IVs, p.EVs, p.Level, p.Nature)
+	p.Stats = &computed
+}
+
+// attachStats fills in Stats for every Pokemon with a single catalog lookup
+func attachStats(ctx context.Context, speciesRepo repository.SpeciesRepository, pokemons []models.Pokemon) error {
+	ids := make([]int, 0, len(pokemons))
+	for _, pokemon := range pokemons {
+		ids = append(ids, pokemon.PokedexID)
+	}
+	species, err := speciesRepo.GetSpeciesByIDs(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to look up species: %w", err)
+	}
+	for i := range pokemons {
+		if s, ok := species[pokemons[i].PokedexID]; ok {
+			setStats(&pokemons[i], &s)
+		}
+	}
+	return nil
+}
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
+func (p *pokemonService) List(ctx context.Context, userId int, opts models.PokemonListOptions) (*models.PokemonPage, error) {
+	opts.UserID = userId
+
+	// Apply defaults and reject nonsense before it reaches SQL
+	if opts.Limit < 0 {
+		return nil, fmt.Errorf("%w: limit cannot be negative", ErrInvalidInput)
+	}
+	if opts.Limit == 0 {
+		opts.Limit = defaultListLimit
+	}
+	if opts.Limit > maxListLimit {
+		opts.Limit = maxListLimit
+	}
+	if opts.SortBy == "" {
+		opts.SortBy = models.SortByCreatedAt
+	}
+	if !opts.SortBy.IsValid() {
+		return nil, fmt.Errorf("%w: cannot sort by %q", ErrInvalidInput, opts.SortBy)
+	}
+	if opts.MinPokedexID < 0 || opts.MaxPokedexID < 0 {
+		return nil, fmt.Errorf("%w: pokedex_id bounds must be positive", ErrInvalidInput)
+	}
+	if opts.MaxPokedexID > 0 && opts.MinPokedexID > opts.MaxPokedexID {
+		return nil, fmt.Errorf("%w: min_pokedex_id is greater than max_pokedex_id", ErrInvalidInput)
+	}
+	if opts.CreatedAfter != nil && opts.CreatedBefore != nil && !opts.CreatedAfter.Before(*opts.CreatedBefore) {
+		return nil, fmt.Errorf("%w: created_after must be before created_before", ErrInvalidInput)
+	}
+	if opts.Type != "" {
+		t, err := models.ParsePokemonType(string(opts.Type))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+		opts.Type = t
+	}
+	opts.NamePrefix = strings.TrimSpace(opts.NamePrefix)
+	if opts.BoxID < 0 {
+		return nil, fmt.Errorf("%w: box must be positive", ErrInvalidInput)
+	}
+	opts.Tag = normalizeTag(opts.Tag)
+	if opts.Gender != "" {
+		opts.Gender = parseGender(string(opts.Gender))
+		if !opts.Gender.IsValid() {
+			return nil, fmt.Errorf("%w: gender must be male, female or genderless", ErrInvalidInput)
+		}
+	}
+	opts.Form = toIdentifier(opts.Form)
+	opts.Ball = toIdentifier(opts.Ball)
+	opts.OriginalTrainer = strings.TrimSpace(opts.OriginalTrainer)
+	opts.CaughtLocation = strings.TrimSpace(opts.CaughtLocation)
+	if opts.CaughtAfter != nil && opts.CaughtBefore != nil && !opts.CaughtAfter.Before(*opts.CaughtBefore) {
+		return nil, fmt.Errorf("%w: caught_after must be before caught_before", ErrInvalidInput)
+	}
+
+	page, err := p.pokemonRepo.ListPokemonByUserID(ctx, opts)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidInput)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to list pokemon: %w", err)
 	}
 
 	// If list is nil (from DB), return an empty slice [] instead of null to frontend.
-	if list == nil {
-		return []models.Pokemon{}, nil
+	if page.Data == nil {
+		page.Data = []models.Pokemon{}
+	}
+	if err := attachStats(ctx, p.speciesRepo, page.Data); err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// Progress counts the species the user has caught, overall and per generation,
+// along with one page of the species still missing.
+func (p *pokemonService) Progress(ctx context.Context, userId int, opts models.DexProgressOptions) (*models.DexProgress, error) {
+	opts.UserID = userId
+
+	if opts.Limit < 0 {
+		return nil, fmt.Errorf("%w: limit cannot be negative", ErrInvalidInput)
+	}
+	if opts.Limit == 0 {
+		opts.Limit = defaultListLimit
+	}
+	if opts.Limit > maxListLimit {
+		opts.Limit = maxListLimit
+	}
+	if opts.GenerationID < 0 {
+		return nil, fmt.Errorf("%w: generation must be positive", ErrInvalidInput)
+	}
+
+	// 1. Counts per generation; the totals and the missing count follow from them
+	counts, err := p.pokemonRepo.CountOwnedByGeneration(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count species: %w", err)
+	}
+	progress := &models.DexProgress{Generations: []models.GenerationProgress{}}
+	if counts != nil {
+		progress.Generations = counts
+	}
+
+	missing, found := 0, opts.GenerationID == 0
+	for _, g := range progress.Generations {
+		progress.Owned += g.Owned
+		progress.Total += g.Total
+		if opts.GenerationID == 0 || g.ID == opts.GenerationID {
+			missing += g.Total - g.Owned
+			found = true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: unknown generation %d", ErrInvalidInput, opts.GenerationID)
+	}
+
+	// 2. One page of missing species
+	page, err := p.pokemonRepo.ListMissingSpecies(ctx, opts)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidInput)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list missing species: %w", err)
+	}
+	if page.Data == nil {
+		page.Data = []models.Species{}
+	}
+	page.Total = missing
+	progress.Missing = *page
+
+	return progress, nil
+}
+
+func (p *pokemonService) Get(ctx context.Context, userId, id int) (*models.Pokemon, error) {
+	pokemon, species, err := p.find(ctx, userId, id)
+	if err != nil {
+		return nil, err
+	}
+	setStats(pokemon, species)
+	return pokemon, nil
+}
+
+// find loads a Pokemon together with its catalog entry, which may be nil
+func (p *pokemonService) find(ctx context.Context, userId, id int) (*models.Pokemon, *models.Species, error) {
+	pokemon, err := p.pokemonRepo.GetPokemonByID(ctx, userId, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch pokemon: %w", err)
+	}
+	// Someone else's Pokemon looks exactly like a missing one
+	if pokemon == nil {
+		return nil, nil, ErrPokemonNotFound
+	}
+
+	species, err := p.speciesRepo.GetSpeciesByID(ctx, pokemon.PokedexID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to look up species: %w", err)
+	}
+	return pokemon, species, nil
+}
+
+func (p *pokemonService) Update(ctx context.Context, userId, id int, input UpdatePokemonInput) (*models.Pokemon, error) {
+	pokemon, species, err := p.find(ctx, userId, id)
+	if err != nil {
+		return nil, err
+	}
+	oldNickname := pokemon.Nickname
+
+	if input.Nickname != nil {
+		pokemon.Nickname = *input.Nickname
+	}
+	if input.Level != nil {
+		pokemon.Level = *input.Level
+	}
+	if input.Nature != nil {
+		pokemon.Nature = *input.Nature
+	}
+	if input.Ability != nil {
+		pokemon.Ability = *input.Ability
+	}
+	if input.IVs != nil {
+		pokemon.IVs = *input.IVs
+	}
+	if input.EVs != nil {
+		pokemon.EVs = *input.EVs
+	}
+	if input.HeldItem != nil {
+		pokemon.HeldItem = *input.HeldItem
+	}
+	if input.Moves != nil {
+		pokemon.Moves = *input.Moves
+	}
+	if input.Friendship != nil {
+		pokemon.Friendship = *input.Friendship
+	}
+	if input.Tags != nil {
+		pokemon.Tags = *input.Tags
+	}
+	if input.Shiny != nil {
+		pokemon.Shiny = *input.Shiny
+	}
+	if input.Gender != nil {
+		pokemon.Gender = models.Gender(*input.Gender)
+	}
+	if input.Form != nil {
+		pokemon.Form = *input.Form
+	}
+	if input.Ball != nil {
+		pokemon.Ball = *input.Ball
+	}
+	if input.OriginalTrainer != nil {
+		pokemon.OriginalTrainer = *input.OriginalTrainer
+	}
+	if input.CaughtAt != nil {
+		pokemon.CaughtAt = *input.CaughtAt
+	}
+	if input.CaughtLocation != nil {
+		pokemon.CaughtLocation = *input.CaughtLocation
+	}
+
+	// Same rules as Create
+	if err := validatePokemon(pokemon); err != nil {
+		return nil, err
+	}
+	// Only re-check the ability when it changes, so a catalog refresh doesn't lock old Pokemon
+	if input.Ability != nil && species != nil {
+		if err := validateAbility(pokemon, species); err != nil {
+			return nil, err
+		}
+	}
+	if (input.Gender != nil || input.Form != nil) && species != nil {
+		if err := validateVariant(pokemon, species); err != nil {
+			return nil, err
+		}
+	}
+	normalizePokemon(pokemon)
+
+	var events []models.PokemonEvent
+	if pokemon.Nickname != oldNickname {
+		events = append(events, models.NewPokemonEvent(pokemon, models.EventRenamed, models.PokemonEventDetails{OldNickname: oldNickname}))
+	}
+	updated, err := p.pokemonRepo.UpdatePokemon(ctx, pokemon, events...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update pokemon: %w", err)
 	}
+	if !updated {
+		return nil, ErrPokemonNotFound
+	}
+
+	setStats(pokemon, species)
+	return pokemon, nil
+}
+
+func (p *pokemonService) Delete(ctx context.Context, userId, id int) error {
+	deleted, err := p.pokemonRepo.DeletePokemon(ctx, userId, id)
+	if err != nil {
+		return fmt.Errorf("failed to release pokemon: %w", err)
+	}
+	if !deleted {
+		return ErrPokemonNotFound
+	}
+	return nil
+}
+
+// Weaknesses computes how every attacking type fares against the Pokemon's stored types
+func (p *pokemonService) Weaknesses(ctx context.Context, userId, id int) (*typechart.DefenseProfile, error) {
+	pokemon, err := p.Get(ctx, userId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := typechart.Defense(pokemon.Types)
+	return &profile, nil
+}
+
+// Evolve turns the Pokemon into the species its conditions allow. It keeps its
+// id, nickname, training and catch date; the species data is replaced.
+func (p *pokemonService) Evolve(ctx context.Context, userId, id int, input EvolveInput) (*models.Pokemon, error) {
+	pokemon, species, err := p.find(ctx, userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if species == nil {
+		return nil, fmt.Errorf("species %d is missing from the catalog", pokemon.PokedexID)
+	}
+
+	evolutions, err := p.speciesRepo.GetEvolutionsFrom(ctx, species.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up evolutions: %w", err)
+	}
+
+	// 1. Keep the evolutions the trainer asked for
+	var candidates []models.Evolution
+	for _, e := range evolutions {
+		if input.IntoSpeciesID == 0 || e.ToSpeciesID == input.IntoSpeciesID {
+			candidates = append(candidates, e)
+		}
+	}
+	if len(candidates) == 0 {
+		if len(evolutions) == 0 {
+			return nil, fmt.Errorf("%w: %s does not evolve", ErrInvalidInput, species.Name)
+		}
+		return nil, fmt.Errorf("%w: %s cannot evolve into species %d", ErrInvalidInput, species.Name, input.IntoSpeciesID)
+	}
+
+	// 2. Check each one's trigger against the stored Pokemon
+	var met []models.Evolution
+	var blockers []string
+	for _, e := range candidates {
+		if reason := evolutionBlocker(pokemon, e, input.Item); reason != "" {
+			blockers = append(blockers, reason)
+			continue
+		}
+		if len(met) == 0 || met[0].ToSpeciesID != e.ToSpeciesID {
+			met = append(met, e)
+		}
+	}
+	switch {
+	case len(met) == 0:
+		return nil, fmt.Errorf("%w: %s cannot evolve yet: %s", ErrInvalidInput, species.Name, strings.Join(blockers, "; "))
+	case len(met) > 1:
+		return nil, fmt.Errorf("%w: %s can evolve in several ways, choose one with into", ErrInvalidInput, species.Name)
+	}
+
+	// 3. Become the new species
+	evolved, err := p.speciesRepo.GetSpeciesByID(ctx, met[0].ToSpeciesID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up species: %w", err)
+	}
+	if evolved == nil {
+		return nil, fmt.Errorf("species %d is missing from the catalog", met[0].ToSpeciesID)
+	}
+	evolvePokemon(pokemon, species, evolved, met[0])
+
+	event := models.NewPokemonEvent(pokemon, models.EventEvolved, models.PokemonEventDetails{
+		FromPokedexID: species.ID,
+		FromName:      species.Name,
+	})
+	updated, err := p.pokemonRepo.UpdatePokemon(ctx, pokemon, event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evolve pokemon: %w", err)
+	}
+	if !updated {
+		return nil, ErrPokemonNotFound
+	}
+
+	setStats(pokemon, evolved)
+	return pokemon, nil
+}
 
-	return list, nil
+// History lists everything that happened to the Pokemon, oldest first,
+// including what happened under previous owners.
+func (p *pokemonService) History(ctx context.Context, userId, id int) ([]models.PokemonEvent, error) {
+	pokemon, err := p.pokemonRepo.GetPokemonByID(ctx, userId, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pokemon: %w", err)
+	}
+	if pokemon == nil {
+		return nil, ErrPokemonNotFound
+	}
+
+	events, err := p.eventRepo.ListPokemonEvents(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list history: %w", err)
+	}
+	if events == nil {
+		return []models.PokemonEvent{}, nil
+	}
+	return events, nil
+}
+
+// Activity pages through the user's events, newest first, released Pokemon included.
+func (p *pokemonService) Activity(ctx context.Context, userId int, opts models.ActivityOptions) (*models.ActivityPage, error) {
+	opts.UserID = userId
+	if opts.Limit < 0 {
+		return nil, fmt.Errorf("%w: limit cannot be negative", ErrInvalidInput)
+	}
+	if opts.Limit == 0 {
+		opts.Limit = defaultListLimit
+	}
+	if opts.Limit > maxListLimit {
+		opts.Limit = maxListLimit
+	}
+
+	page, err := p.eventRepo.ListActivity(ctx, opts)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidInput)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list activity: %w", err)
+	}
+	if page.Data == nil {
+		page.Data = []models.PokemonEvent{}
+	}
+	return page, nil
+}
+
+// everstone stops its holder from evolving
+const everstone = "everstone"
+
+// evolutionBlocker explains why the Pokemon can't evolve this way right now,
+// or returns "" if it can. Time of day is not checked.
+func evolutionBlocker(p *models.Pokemon, e models.Evolution, item string) string {
+	if p.HeldItem == everstone {
+		return "holds an everstone"
+	}
+	if e.OtherConditions {
+		return fmt.Sprintf("evolving into species %d needs conditions the Pokedex doesn't track", e.ToSpeciesID)
+	}
+
+	switch e.Trigger {
+	case models.TriggerLevelUp:
+		if p.Level < e.MinLevel {
+			return fmt.Sprintf("needs level %d", e.MinLevel)
+		}
+		if p.Friendship < e.MinFriendship {
+			return fmt.Sprintf("needs %d friendship", e.MinFriendship)
+		}
+		if e.HeldItem != "" && p.HeldItem != e.HeldItem {
+			return fmt.Sprintf("needs to hold %s", e.HeldItem)
+		}
+		return ""
+	case models.TriggerUseItem:
+		if toIdentifier(item) != e.Item {
+			return fmt.Sprintf("needs a %s", e.Item)
+		}
+		return ""
+	case models.TriggerTrade:
+		return "evolves when traded"
+	default:
+		return fmt.Sprintf("the %s trigger is not supported", e.Trigger)
+	}
+}
+
+// tradeEvolves reports whether trading the Pokemon for partner triggers this evolution
+func tradeEvolves(p, partner *models.Pokemon, e models.Evolution) bool {
+	return e.Trigger == models.TriggerTrade &&
+		!e.OtherConditions &&
+		p.HeldItem != everstone &&
+		(e.HeldItem == "" || p.HeldItem == e.HeldItem) &&
+		(e.TradeSpeciesID == 0 || partner.PokedexID == e.TradeSpeciesID)
+}
+
+// evolvePokemon swaps in the evolved species' data. A held item the evolution
+// needed is used up, and the ability keeps its slot where the new species has one.
+func evolvePokemon(p *models.Pokemon, from, to *models.Species, e models.Evolution) {
+	// A Pokemon without a nickname carries its species name, so rename it too
+	if p.Nickname == p.Name {
+		p.Nickname = to.Name
+	}
+	p.PokedexID = to.ID
+	p.Name = to.Name
+	p.Types = to.Types
+	p.Height = to.Height
+	p.Weight = to.Weight
+	// Regional forms evolve into the same form, e.g. Alolan Vulpix into Alolan Ninetales
+	if !to.HasForm(p.Form) {
+		p.Form = ""
+	}
+
+	if e.HeldItem != "" {
+		p.HeldItem = ""
+	}
+
+	ability := ""
+	for slot, a := range from.Abilities {
+		if a == p.Ability && slot < len(to.Abilities) {
+			ability = to.Abilities[slot]
+		}
+	}
+	if ability == "" && len(to.Abilities) > 0 {
+		ability = to.Abilities[0]
+	}
+	p.Ability = ability
 }
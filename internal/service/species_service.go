@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/repository"
+)
+
+var ErrSpeciesNotFound = errors.New("species not found")
+
+// SpeciesService serves read-only catalog data
+type SpeciesService interface {
+	EvolutionChain(ctx context.Context, speciesId int) (*models.EvolutionChain, error)
+}
+
+type speciesService struct {
+	speciesRepo repository.SpeciesRepository
+}
+
+func NewSpeciesService(speciesRepo repository.SpeciesRepository) SpeciesService {
+	return &speciesService{
+		speciesRepo: speciesRepo,
+	}
+}
+
+// EvolutionChain returns the whole family of the species, from its unevolved form down
+func (s *speciesService) EvolutionChain(ctx context.Context, speciesId int) (*models.EvolutionChain, error) {
+	species, err := s.speciesRepo.GetSpeciesByID(ctx, speciesId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up species: %w", err)
+	}
+	if species == nil {
+		return nil, ErrSpeciesNotFound
+	}
+
+	// Species imported without chain data stand alone
+	if species.ChainID == 0 {
+		return &models.EvolutionChain{Root: models.EvolutionNode{
+			SpeciesID: species.ID,
+			Name:      species.Name,
+			EvolvesTo: []models.EvolutionNode{},
+		}}, nil
+	}
+
+	members, evolutions, err := s.speciesRepo.GetEvolutionChain(ctx, species.ChainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load evolution chain: %w", err)
+	}
+
+	// 1. Index the chain: who evolves into whom, and how
+	byID := make(map[int]models.Species, len(members))
+	for _, m := range members {
+		byID[m.ID] = m
+	}
+	children := make(map[int][]int)
+	var root *models.Species
+	for i, m := range members {
+		if m.EvolvesFromID != nil {
+			if _, ok := byID[*m.EvolvesFromID]; ok {
+				children[*m.EvolvesFromID] = append(children[*m.EvolvesFromID], m.ID)
+				continue
+			}
+		}
+		root = &members[i]
+	}
+	if root == nil {
+		return nil, fmt.Errorf("evolution chain %d has no root", species.ChainID)
+	}
+
+	methods := make(map[int][]models.Evolution) // Keyed by the evolved species
+	for _, e := range evolutions {
+		methods[e.ToSpeciesID] = append(methods[e.ToSpeciesID], e)
+	}
+
+	// 2. Build the tree from the root down
+	var build func(id int) models.EvolutionNode
+	build = func(id int) models.EvolutionNode {
+		node := models.EvolutionNode{
+			SpeciesID: id,
+			Name:      byID[id].Name,
+			Methods:   methods[id],
+			EvolvesTo: []models.EvolutionNode{},
+		}
+		for _, child := range children[id] {
+			node.EvolvesTo = append(node.EvolvesTo, build(child))
+		}
+		return node
+	}
+
+	return &models.EvolutionChain{ID: species.ChainID, Root: build(root.ID)}, nil
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/repository"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/typechart"
+)
+
+var ErrTeamNotFound = errors.New("team not found")
+
+// maxTeamNameLength matches the name column size
+const maxTeamNameLength = 100
+
+// UpdateTeamInput holds a partial update. Nil fields are left unchanged;
+// a non-nil PokemonIDs replaces the whole party.
+type UpdateTeamInput struct {
+	Name       *string
+	PokemonIDs *[]int
+}
+
+type TeamService interface {
+	Create(ctx context.Context, userId int, name string, pokemonIds []int) (*models.Team, error)
+	List(ctx context.Context, userId int) ([]models.Team, error)
+	Get(ctx context.Context, userId, id int) (*models.Team, error)
+	Update(ctx context.Context, userId, id int, input UpdateTeamInput) (*models.Team, error)
+	Delete(ctx context.Context, userId, id int) error
+	Analyze(ctx context.Context, userId, id int) (*typechart.TeamAnalysis, error)
+}
+
+type teamService struct {
+	teamRepo    repository.TeamRepository
+	speciesRepo repository.SpeciesRepository
+}
+
+func NewTeamService(teamRepo repository.TeamRepository, speciesRepo repository.SpeciesRepository) TeamService {
+	return &teamService{
+		teamRepo:    teamRepo,
+		speciesRepo: speciesRepo,
+	}
+}
+
+func validateTeamName(name string) (string, error) {
+	clean := strings.TrimSpace(name)
+	if clean == "" {
+		return "", fmt.Errorf("%w: team name cannot be empty", ErrInvalidInput)
+	}
+	if len([]rune(clean)) > maxTeamNameLength {
+		return "", fmt.Errorf("%w: team name cannot be longer than %d characters", ErrInvalidInput, maxTeamNameLength)
+	}
+	return clean, nil
+}
+
+// validateTeamMembers enforces the party size and forbids listing a Pokemon twice.
+// Ownership is checked by the repository inside its transaction.
+func validateTeamMembers(pokemonIds []int) error {
+	if len(pokemonIds) > models.MaxTeamSize {
+		return fmt.Errorf("%w: a team holds at most %d pokemon", ErrInvalidInput, models.MaxTeamSize)
+	}
+	seen := make(map[int]bool, len(pokemonIds))
+	for _, id := range pokemonIds {
+		if id <= 0 {
+			return fmt.Errorf("%w: pokemon ids must be positive", ErrInvalidInput)
+		}
+		if seen[id] {
+			return fmt.Errorf("%w: pokemon %d is listed twice", ErrInvalidInput, id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+func (s *teamService) Create(ctx context.Context, userId int, name string, pokemonIds []int) (*models.Team, error) {
+	cleanName, err := validateTeamName(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateTeamMembers(pokemonIds); err != nil {
+		return nil, err
+	}
+
+	team := &models.Team{UserID: userId, Name: cleanName}
+	if err := s.teamRepo.CreateTeam(ctx, team, pokemonIds); err != nil {
+		// "Not your Pokemon" is the client's mistake, not ours
+		if errors.Is(err, repository.ErrPokemonNotOwned) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+		return nil, fmt.Errorf("failed to save team: %w", err)
+	}
+
+	// Reload so members come back with their full data
+	return s.Get(ctx, userId, team.ID)
+}
+
+func (s *teamService) List(ctx context.Context, userId int) ([]models.Team, error) {
+	teams, err := s.teamRepo.ListTeams(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list teams: %w", err)
+	}
+	if teams == nil {
+		return []models.Team{}, nil
+	}
+	for i := range teams {
+		if err := attachStats(ctx, s.speciesRepo, teams[i].Members); err != nil {
+			return nil, err
+		}
+	}
+	return teams, nil
+}
+
+func (s *teamService) Get(ctx context.Context, userId, id int) (*models.Team, error) {
+	team, err := s.teamRepo.GetTeam(ctx, userId, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch team: %w", err)
+	}
+	if team == nil {
+		return nil, ErrTeamNotFound
+	}
+	if err := attachStats(ctx, s.speciesRepo, team.Members); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+func (s *teamService) Update(ctx context.Context, userId, id int, input UpdateTeamInput) (*models.Team, error) {
+	team, err := s.Get(ctx, userId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Name != nil {
+		if team.Name, err = validateTeamName(*input.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	var pokemonIds []int
+	if input.PokemonIDs != nil {
+		pokemonIds = *input.PokemonIDs
+		if err := validateTeamMembers(pokemonIds); err != nil {
+			return nil, err
+		}
+		// Non-nil tells the repository to replace members, even with an empty party
+		if pokemonIds == nil {
+			pokemonIds = []int{}
+		}
+	}
+
+	updated, err := s.teamRepo.UpdateTeam(ctx, team, pokemonIds)
+	if err != nil {
+		if errors.Is(err, repository.ErrPokemonNotOwned) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+		return nil, fmt.Errorf("failed to update team: %w", err)
+	}
+	if !updated {
+		return nil, ErrTeamNotFound
+	}
+
+	return s.Get(ctx, userId, id)
+}
+
+func (s *teamService) Delete(ctx context.Context, userId, id int) error {
+	deleted, err := s.teamRepo.DeleteTeam(ctx, userId, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete team: %w", err)
+	}
+	if !deleted {
+		return ErrTeamNotFound
+	}
+	return nil
+}
+
+// Analyze reports shared weaknesses, resistances and offensive gaps from the members' types
+func (s *teamService) Analyze(ctx context.Context, userId, id int) (*typechart.TeamAnalysis, error) {
+	team, err := s.Get(ctx, userId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]typechart.TeamMember, len(team.Members))
+	for i, p := range team.Members {
+		members[i] = typechart.TeamMember{ID: p.ID, Types: p.Types}
+	}
+
+	analysis := typechart.AnalyzeTeam(members)
+	return &analysis, nil
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+	"github.com/sanskarchoudhry/pokedex-backend/internal/repository"
+)
+
+var (
+	ErrTradeNotFound = errors.New("trade not found")
+	// ErrTradeConflict means the trade can't go ahead any more, e.g. it was
+	// already answered or one of the Pokemon changed hands.
+	ErrTradeConflict = errors.New("trade conflict")
+)
+
+// ProposeTradeInput offers one of the caller's Pokemon for one of the recipient's
+type ProposeTradeInput struct {
+	OfferedPokemonID   int
+	RecipientID        int
+	RequestedPokemonID int
+}
+
+type TradeService interface {
+	Propose(ctx context.Context, userId int, input ProposeTradeInput) (*models.Trade, error)
+	List(ctx context.Context, userId int, status string) ([]models.Trade, error)
+	Get(ctx context.Context, userId, id int) (*models.Trade, error)
+	Accept(ctx context.Context, userId, id int) (*models.Trade, error)
+	Decline(ctx context.Context, userId, id int) (*models.Trade, error)
+	Cancel(ctx context.Context, userId, id int) (*models.Trade, error)
+}
+
+type tradeService struct {
+	tradeRepo   repository.TradeRepository
+	pokemonRepo repository.PokemonRepository
+	speciesRepo repository.SpeciesRepository
+}
+
+func NewTradeService(tradeRepo repository.TradeRepository, pokemonRepo repository.PokemonRepository, speciesRepo repository.SpeciesRepository) TradeService {
+	return &tradeService{
+		tradeRepo:   tradeRepo,
+		pokemonRepo: pokemonRepo,
+		speciesRepo: speciesRepo,
+	}
+}
+
+func (s *tradeService) Propose(ctx context.Context, userId int, input ProposeTradeInput) (*models.Trade, error) {
+	if input.OfferedPokemonID <= 0 || input.RequestedPokemonID <= 0 || input.RecipientID <= 0 {
+		return nil, fmt.Errorf("%w: offered_pokemon_id, recipient_id and requested_pokemon_id must be positive", ErrInvalidInput)
+	}
+	if input.RecipientID == userId {
+		return nil, fmt.Errorf("%w: you cannot trade with yourself", ErrInvalidInput)
+	}
+
+	// Both Pokemon must belong to the right trainer now; accept checks again
+	offered, err := s.pokemonRepo.GetPokemonByID(ctx, userId, input.OfferedPokemonID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pokemon: %w", err)
+	}
+	if offered == nil {
+		return nil, fmt.Errorf("%w: you don't own pokemon %d", ErrInvalidInput, input.OfferedPokemonID)
+	}
+	requested, err := s.pokemonRepo.GetPokemonByID(ctx, input.RecipientID, input.RequestedPokemonID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pokemon: %w", err)
+	}
+	if requested == nil {
+		return nil, fmt.Errorf("%w: user %d doesn't own pokemon %d", ErrInvalidInput, input.RecipientID, input.RequestedPokemonID)
+	}
+
+	trade := &models.Trade{
+		ProposerID:         userId,
+		RecipientID:        input.RecipientID,
+		OfferedPokemonID:   offered.ID,
+		RequestedPokemonID: requested.ID,
+	}
+	if err := s.tradeRepo.CreateTrade(ctx, trade); err != nil {
+		return nil, fmt.Errorf("failed to save trade: %w", err)
+	}
+	return trade, nil
+}
+
+func (s *tradeService) List(ctx context.Context, userId int, status string) ([]models.Trade, error) {
+	tradeStatus := models.TradeStatus(status)
+	if status != "" && !tradeStatus.IsValid() {
+		return nil, fmt.Errorf("%w: unknown trade status %q", ErrInvalidInput, status)
+	}
+
+	trades, err := s.tradeRepo.ListTrades(ctx, userId, tradeStatus)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trades: %w", err)
+	}
+	if trades == nil {
+		return []models.Trade{}, nil
+	}
+	return trades, nil
+}
+
+func (s *tradeService) Get(ctx context.Context, userId, id int) (*models.Trade, error) {
+	trade, err := s.tradeRepo.GetTrade(ctx, userId, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch trade: %w", err)
+	}
+	if trade == nil {
+		return nil, ErrTradeNotFound
+	}
+	return trade, nil
+}
+
+// Accept swaps the two Pokemon. Only the recipient can accept.
+func (s *tradeService) Accept(ctx context.Context, userId, id int) (*models.Trade, error) {
+	accepted, err := s.tradeRepo.AcceptTrade(ctx, userId, id, func(pokemon, partner *models.Pokemon) error {
+		return s.evolveOnArrival(ctx, pokemon, partner)
+	})
+	if errors.Is(err, repository.ErrTradeNotPending) || errors.Is(err, repository.ErrPokemonNotOwned) {
+		return nil, fmt.Errorf("%w: %v", ErrTradeConflict, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to accept trade: %w", err)
+	}
+	if !accepted {
+		return nil, ErrTradeNotFound
+	}
+	return s.Get(ctx, userId, id)
+}
+
+// evolveOnArrival applies the first trade evolution the species catalog allows
+func (s *tradeService) evolveOnArrival(ctx context.Context, pokemon, partner *models.Pokemon) error {
+	evolutions, err := s.speciesRepo.GetEvolutionsFrom(ctx, pokemon.PokedexID)
+	if err != nil {
+		return fmt.Errorf("failed to look up evolutions: %w", err)
+	}
+
+	for _, e := range evolutions {
+		if !tradeEvolves(pokemon, partner, e) {
+			continue
+		}
+
+		species, err := s.speciesRepo.GetSpeciesByIDs(ctx, []int{e.FromSpeciesID, e.ToSpeciesID})
+		if err != nil {
+			return fmt.Errorf("failed to look up species: %w", err)
+		}
+		from, okFrom := species[e.FromSpeciesID]
+		to, okTo := species[e.ToSpeciesID]
+		if !okFrom || !okTo {
+			return fmt.Errorf("evolution %d -> %d references a species missing from the catalog", e.FromSpeciesID, e.ToSpeciesID)
+		}
+		evolvePokemon(pokemon, &from, &to, e)
+		return nil
+	}
+	return nil
+}
+
+// Decline turns the trade down. Only the recipient can decline.
+func (s *tradeService) Decline(ctx context.Context, userId, id int) (*models.Trade, error) {
+	return s.resolve(ctx, userId, id, s.tradeRepo.DeclineTrade)
+}
+
+// Cancel withdraws the trade. Only the proposer can cancel.
+func (s *tradeService) Cancel(ctx context.Context, userId, id int) (*models.Trade, error) {
+	return s.resolve(ctx, userId, id, s.tradeRepo.CancelTrade)
+}
+
+func (s *tradeService) resolve(ctx context.Context, userId, id int, close func(ctx context.Context, userID, id int) (bool, error)) (*models.Trade, error) {
+	closed, err := close(ctx, userId, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update trade: %w", err)
+	}
+	if closed {
+		return s.Get(ctx, userId, id)
+	}
+
+	// Nothing matched: either it isn't this user's to close, or it's already closed
+	trade, err := s.Get(ctx, userId, id)
+	if err != nil {
+		return nil, err
+	}
+	if trade.Status != models.TradePending {
+		return nil, fmt.Errorf("%w: trade is already %s", ErrTradeConflict, trade.Status)
+	}
+	return nil, ErrTradeNotFound
+}
@@ -0,0 +1,336 @@
+// Package showdown reads and writes team pastes in Pokemon Showdown's text format:
+//
+//	Sparky (Pikachu) (F) @ Light Ball
+//	Ability: Static
+//	Level: 50
+//	Shiny: Yes
+//	EVs: 252 Atk / 4 SpD / 252 Spe
+//	Jolly Nature
+//	IVs: 0 SpA
+//	- Volt Tackle
+//	- Iron Tail
+//
+// Sets are separated by blank lines. Names are kept exactly as written when
+// parsing; resolving them against the catalog is up to the caller.
+package showdown
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+)
+
+// Showdown's defaults for lines a paste leaves out
+const (
+	DefaultLevel     = 100
+	DefaultIV        = 31
+	DefaultHappiness = 255
+)
+
+// statNames are Showdown's stat abbreviations in StatSpread order
+var statNames = [6]string{"HP", "Atk", "Def", "SpA", "SpD", "Spe"}
+
+// ignoredFields are valid Showdown lines the Pokedex doesn't store
+var ignoredFields = map[string]bool{
+	"tera type":     true,
+	"gigantamax":    true,
+	"dynamax level": true,
+	"hidden power":  true,
+}
+
+// genderMarks are the header suffixes Showdown uses for gender
+var genderMarks = map[string]models.Gender{"(M)": models.GenderMale, "(F)": models.GenderFemale}
+
+// Set is one parsed Pokemon and the line its block starts on.
+type Set struct {
+	Line    int
+	Pokemon models.Pokemon
+}
+
+// LineError points at the paste line that couldn't be understood.
+type LineError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+func (e LineError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// ParseError collects every LineError in a paste, so users can fix them all at once.
+type ParseError struct {
+	Errors []LineError
+}
+
+func (e *ParseError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", e.Errors[0].Error(), len(e.Errors)-1)
+}
+
+// Parse reads every set in a paste. Pokemon.Name holds the species as written.
+// Omitted IVs and happiness default to 31 and 255 and an omitted level to 100, like Showdown.
+// On failure the error is a *ParseError.
+func Parse(paste string) ([]Set, error) {
+	var sets []Set
+	var errs []LineError
+	var current *Set
+
+	lines := strings.Split(strings.ReplaceAll(paste, "\r\n", "\n"), "\n")
+	for i, raw := range lines {
+		lineNo := i + 1
+		line := strings.TrimSpace(raw)
+
+		// Blank lines end a set; "=== [gen9] Team ===" headers are skipped
+		if line == "" || strings.HasPrefix(line, "===") {
+			if current != nil {
+				sets = append(sets, *current)
+				current = nil
+			}
+			continue
+		}
+
+		if current == nil {
+			current = &Set{Line: lineNo, Pokemon: newPokemon()}
+			if err := parseHeader(line, &current.Pokemon); err != nil {
+				errs = append(errs, LineError{lineNo, err.Error()})
+			}
+			continue
+		}
+
+		if err := parseLine(line, &current.Pokemon); err != nil {
+			errs = append(errs, LineError{lineNo, err.Error()})
+		}
+	}
+	if current != nil {
+		sets = append(sets, *current)
+	}
+
+	if len(errs) > 0 {
+		return nil, &ParseError{Errors: errs}
+	}
+	if len(sets) == 0 {
+		return nil, &ParseError{Errors: []LineError{{1, "paste contains no Pokemon"}}}
+	}
+	return sets, nil
+}
+
+func newPokemon() models.Pokemon {
+	return models.Pokemon{
+		Level: DefaultLevel,
+		IVs: models.StatSpread{
+			HP: DefaultIV, Attack: DefaultIV, Defense: DefaultIV,
+			SpAttack: DefaultIV, SpDefense: DefaultIV, Speed: DefaultIV,
+		},
+		Moves:      []string{},
+		Friendship: DefaultHappiness,
+	}
+}
+
+// parseHeader reads "Nickname (Species) (F) @ Item"; everything but the species is optional
+func parseHeader(line string, p *models.Pokemon) error {
+	name, item, _ := strings.Cut(line, " @ ")
+	p.HeldItem = strings.TrimSpace(item)
+
+	name = strings.TrimSpace(name)
+	for mark, gender := range genderMarks {
+		if trimmed, ok := strings.CutSuffix(name, mark); ok {
+			name = strings.TrimSpace(trimmed)
+			p.Gender = gender
+		}
+	}
+
+	if strings.HasSuffix(name, ")") {
+		if open := strings.LastIndex(name, " ("); open > 0 {
+			p.Nickname = strings.TrimSpace(name[:open])
+			name = name[open+2 : len(name)-1]
+		}
+	}
+	p.Name = strings.TrimSpace(name)
+	if p.Name == "" {
+		return fmt.Errorf("missing species")
+	}
+	return nil
+}
+
+func parseLine(line string, p *models.Pokemon) error {
+	if move, ok := strings.CutPrefix(line, "-"); ok {
+		if len(p.Moves) == models.MaxMoves {
+			return fmt.Errorf("a Pokemon knows at most %d moves", models.MaxMoves)
+		}
+		move = strings.TrimSpace(move)
+		if move == "" {
+			return fmt.Errorf("missing move name")
+		}
+		p.Moves = append(p.Moves, move)
+		return nil
+	}
+
+	if nature, ok := strings.CutSuffix(line, " Nature"); ok {
+		p.Nature = strings.TrimSpace(nature)
+		return nil
+	}
+
+	field, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return fmt.Errorf("unrecognized line %q", line)
+	}
+	value = strings.TrimSpace(value)
+
+	switch key := strings.ToLower(strings.TrimSpace(field)); key {
+	case "ability":
+		p.Ability = value
+	case "level":
+		level, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid level %q", value)
+		}
+		p.Level = level
+	case "happiness":
+		happiness, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid happiness %q", value)
+		}
+		p.Friendship = happiness
+	case "shiny":
+		switch strings.ToLower(value) {
+		case "yes":
+			p.Shiny = true
+		case "no":
+			p.Shiny = false
+		default:
+			return fmt.Errorf("invalid shiny %q, expected Yes or No", value)
+		}
+	case "pokeball":
+		p.Ball = value
+	case "evs":
+		return parseSpread(value, &p.EVs)
+	case "ivs":
+		return parseSpread(value, &p.IVs)
+	default:
+		if !ignoredFields[key] {
+			return fmt.Errorf("unrecognized line %q", line)
+		}
+	}
+	return nil
+}
+
+// parseSpread reads "252 Atk / 4 SpD / 252 Spe", overwriting only the stats it names
+func parseSpread(value string, spread *models.StatSpread) error {
+	fields := statFields(spread)
+	for _, part := range strings.Split(value, "/") {
+		var amount int
+		var stat string
+		if _, err := fmt.Sscanf(strings.TrimSpace(part), "%d %s", &amount, &stat); err != nil {
+			return fmt.Errorf("invalid stat %q", strings.TrimSpace(part))
+		}
+		i := statIndex(stat)
+		if i < 0 {
+			return fmt.Errorf("unknown stat %q", stat)
+		}
+		*fields[i] = amount
+	}
+	return nil
+}
+
+func statIndex(name string) int {
+	for i, s := range statNames {
+		if strings.EqualFold(s, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func statFields(s *models.StatSpread) [6]*int {
+	return [6]*int{&s.HP, &s.Attack, &s.Defense, &s.SpAttack, &s.SpDefense, &s.Speed}
+}
+
+// Format renders Pokemon as a paste that Parse (and Showdown) read back unchanged.
+func Format(pokemons []models.Pokemon) string {
+	var b strings.Builder
+	for i, p := range pokemons {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		formatSet(&b, p)
+	}
+	return b.String()
+}
+
+func formatSet(b *strings.Builder, p models.Pokemon) {
+	name := p.Name
+	if p.Form != "" {
+		name += "-" + p.Form
+	}
+	species := DisplayName(name, "-")
+	if p.Nickname != "" && p.Nickname != p.Name {
+		fmt.Fprintf(b, "%s (%s)", p.Nickname, species)
+	} else {
+		b.WriteString(species)
+	}
+	switch p.Gender {
+	case models.GenderMale:
+		b.WriteString(" (M)")
+	case models.GenderFemale:
+		b.WriteString(" (F)")
+	}
+	if p.HeldItem != "" {
+		fmt.Fprintf(b, " @ %s", DisplayName(p.HeldItem, " "))
+	}
+	b.WriteString("\n")
+
+	if p.Ability != "" {
+		fmt.Fprintf(b, "Ability: %s\n", DisplayName(p.Ability, " "))
+	}
+	if p.Level != DefaultLevel {
+		fmt.Fprintf(b, "Level: %d\n", p.Level)
+	}
+	if p.Shiny {
+		b.WriteString("Shiny: Yes\n")
+	}
+	if p.Ball != "" && p.Ball != "poke-ball" {
+		fmt.Fprintf(b, "Pokeball: %s\n", DisplayName(p.Ball, " "))
+	}
+	if p.Friendship != DefaultHappiness {
+		fmt.Fprintf(b, "Happiness: %d\n", p.Friendship)
+	}
+	if evs := formatSpread(p.EVs, 0); evs != "" {
+		fmt.Fprintf(b, "EVs: %s\n", evs)
+	}
+	if p.Nature != "" {
+		fmt.Fprintf(b, "%s Nature\n", DisplayName(p.Nature, " "))
+	}
+	if ivs := formatSpread(p.IVs, DefaultIV); ivs != "" {
+		fmt.Fprintf(b, "IVs: %s\n", ivs)
+	}
+	for _, move := range p.Moves {
+		fmt.Fprintf(b, "- %s\n", DisplayName(move, " "))
+	}
+}
+
+// formatSpread lists the stats that differ from the default, e.g. "252 Atk / 4 SpD"
+func formatSpread(spread models.StatSpread, defaultValue int) string {
+	var parts []string
+	for i, v := range spread.Values() {
+		if v != defaultValue {
+			parts = append(parts, fmt.Sprintf("%d %s", v, statNames[i]))
+		}
+	}
+	return strings.Join(parts, " / ")
+}
+
+// DisplayName turns a PokeAPI identifier into a title-cased name, joining words
+// with sep: ("choice-scarf", " ") -> "Choice Scarf", ("ho-oh", "-") -> "Ho-Oh".
+func DisplayName(identifier, sep string) string {
+	words := strings.Split(identifier, "-")
+	for i, w := range words {
+		if w != "" {
+			words[i] = strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	return strings.Join(words, sep)
+}
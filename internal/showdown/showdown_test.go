@@ -0,0 +1,140 @@
+package showdown
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+)
+
+// pokemon returns a Pokemon with Showdown's defaults, as Parse builds it
+func pokemon(name string) models.Pokemon {
+	p := newPokemon()
+	p.Name = name
+	return p
+}
+
+func TestRoundTrip(t *testing.T) {
+	full := pokemon("Pikachu")
+	full.Nickname = "Sparky"
+	full.Gender = models.GenderFemale
+	full.HeldItem = "Light Ball"
+	full.Ability = "Static"
+	full.Level = 50
+	full.Shiny = true
+	full.Ball = "Ultra Ball"
+	full.Friendship = 70
+	full.EVs = models.StatSpread{Attack: 252, SpDefense: 4, Speed: 252}
+	full.Nature = "Jolly"
+	full.IVs.SpAttack = 0
+	full.Moves = []string{"Volt Tackle", "Iron Tail", "Quick Attack", "Protect"}
+
+	male := pokemon("Garchomp")
+	male.Gender = models.GenderMale
+	male.Moves = []string{"Earthquake"}
+
+	form := pokemon("Vulpix-Alola")
+	form.Nickname = "Snowy"
+	form.Ability = "Snow Cloak"
+
+	tests := []struct {
+		name string
+		sets []models.Pokemon
+	}{
+		{"every field", []models.Pokemon{full}},
+		{"gender without nickname", []models.Pokemon{male}},
+		{"form with nickname", []models.Pokemon{form}},
+		{"species only", []models.Pokemon{pokemon("Ditto")}},
+		{"several sets", []models.Pokemon{full, male, form}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paste := Format(tt.sets)
+			sets, err := Parse(paste)
+			if err != nil {
+				t.Fatalf("Parse(Format(x)) error: %v\npaste:\n%s", err, paste)
+			}
+			if len(sets) != len(tt.sets) {
+				t.Fatalf("Parse(Format(x)) returned %d sets, want %d\npaste:\n%s", len(sets), len(tt.sets), paste)
+			}
+			for i, set := range sets {
+				if !reflect.DeepEqual(set.Pokemon, tt.sets[i]) {
+					t.Errorf("set %d = %+v, want %+v\npaste:\n%s", i, set.Pokemon, tt.sets[i], paste)
+				}
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		pokemon models.Pokemon
+		want    string
+	}{
+		{
+			"defaults are omitted",
+			pokemon("Ditto"),
+			"Ditto\n",
+		},
+		{
+			"form joins the species",
+			models.Pokemon{Name: "vulpix", Form: "alola", Gender: models.GenderFemale, Level: DefaultLevel, IVs: newPokemon().IVs, Friendship: DefaultHappiness},
+			"Vulpix-Alola (F)\n",
+		},
+		{
+			"identifiers become display names",
+			models.Pokemon{Name: "ho-oh", HeldItem: "choice-scarf", Ball: "poke-ball", Nature: "adamant", Level: 70, IVs: newPokemon().IVs, Friendship: DefaultHappiness, Moves: []string{"sacred-fire"}},
+			"Ho-Oh @ Choice Scarf\nLevel: 70\nAdamant Nature\n- Sacred Fire\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Format([]models.Pokemon{tt.pokemon}); got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseReportsEveryBadLine(t *testing.T) {
+	paste := strings.Join([]string{
+		"Pikachu",
+		"Level: fifty",
+		"- Thunderbolt",
+		"",
+		"Eevee @ Leftovers",
+		"EVs: 252 Atk / 4 Foo",
+		"Shiny: Maybe",
+	}, "\n")
+
+	_, err := Parse(paste)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("Parse() error = %v, want a *ParseError", err)
+	}
+
+	want := []LineError{
+		{2, `invalid level "fifty"`},
+		{6, `unknown stat "Foo"`},
+		{7, `invalid shiny "Maybe", expected Yes or No`},
+	}
+	if !reflect.DeepEqual(parseErr.Errors, want) {
+		t.Errorf("Parse() errors = %v, want %v", parseErr.Errors, want)
+	}
+	if got, want := parseErr.Error(), `line 2: invalid level "fifty" (and 2 more errors)`; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestParseEmptyPaste(t *testing.T) {
+	_, err := Parse("=== [gen9] Team ===\n\n")
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || len(parseErr.Errors) != 1 {
+		t.Fatalf("Parse() error = %v, want one line error", err)
+	}
+}
@@ -0,0 +1,99 @@
+// Package stats computes final battle stats with the formulas used since Generation III.
+package stats
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+)
+
+// Limits enforced on caught Pokemon
+const (
+	MaxIV      = 31
+	MaxEV      = 252
+	MaxEVTotal = 510
+	MinLevel   = 1
+	MaxLevel   = 100
+)
+
+const (
+	shedinjaHP = 1 // A base HP of 1 always yields 1 HP
+	// Natures scale a stat by 110% or 90%, in integer percent to floor like the games
+	natureUp    = 110
+	natureDown  = 90
+	natureScale = 100
+)
+
+// Stat names a non-HP stat that a nature can raise or lower.
+type Stat int
+
+const (
+	None Stat = iota
+	Attack
+	Defense
+	SpAttack
+	SpDefense
+	Speed
+)
+
+// nature is the stat raised and the stat lowered; neutral natures touch neither.
+type nature struct {
+	up, down Stat
+}
+
+var natures = map[string]nature{
+	"hardy": {None, None}, "lonely": {Attack, Defense}, "brave": {Attack, Speed},
+	"adamant": {Attack, SpAttack}, "naughty": {Attack, SpDefense},
+	"bold": {Defense, Attack}, "docile": {None, None}, "relaxed": {Defense, Speed},
+	"impish": {Defense, SpAttack}, "lax": {Defense, SpDefense},
+	"timid": {Speed, Attack}, "hasty": {Speed, Defense}, "serious": {None, None},
+	"jolly": {Speed, SpAttack}, "naive": {Speed, SpDefense},
+	"modest": {SpAttack, Attack}, "mild": {SpAttack, Defense}, "quiet": {SpAttack, Speed},
+	"bashful": {None, None}, "rash": {SpAttack, SpDefense},
+	"calm": {SpDefense, Attack}, "gentle": {SpDefense, Defense}, "sassy": {SpDefense, Speed},
+	"careful": {SpDefense, SpAttack}, "quirky": {None, None},
+}
+
+// DefaultNature is neutral, so it never changes a stat.
+const DefaultNature = "hardy"
+
+// ParseNature normalizes a nature name ("Adamant" -> "adamant") and rejects unknown ones.
+func ParseNature(s string) (string, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if _, ok := natures[name]; !ok {
+		return "", fmt.Errorf("unknown nature %q", s)
+	}
+	return name, nil
+}
+
+// Compute returns the final stats of a Pokemon. The nature must be valid.
+func Compute(base, ivs, evs models.StatSpread, level int, natureName string) models.StatSpread {
+	n := natures[natureName]
+
+	hp := shedinjaHP
+	if base.HP != shedinjaHP {
+		hp = (2*base.HP+ivs.HP+evs.HP/4)*level/100 + level + 10
+	}
+
+	return models.StatSpread{
+		HP:        hp,
+		Attack:    other(base.Attack, ivs.Attack, evs.Attack, level, n, Attack),
+		Defense:   other(base.Defense, ivs.Defense, evs.Defense, level, n, Defense),
+		SpAttack:  other(base.SpAttack, ivs.SpAttack, evs.SpAttack, level, n, SpAttack),
+		SpDefense: other(base.SpDefense, ivs.SpDefense, evs.SpDefense, level, n, SpDefense),
+		Speed:     other(base.Speed, ivs.Speed, evs.Speed, level, n, Speed),
+	}
+}
+
+// other applies the non-HP formula, flooring after every step like the games do
+func other(base, iv, ev, level int, n nature, stat Stat) int {
+	value := (2*base+iv+ev/4)*level/100 + 5
+	switch stat {
+	case n.up:
+		value = value * natureUp / natureScale
+	case n.down:
+		value = value * natureDown / natureScale
+	}
+	return value
+}
@@ -0,0 +1,87 @@
+package typechart
+
+import (
+	"sort"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+)
+
+// TeamMember is a party member as seen by the type chart.
+type TeamMember struct {
+	ID    int
+	Types models.Types
+}
+
+// TypeReport lists which members take an attacking type badly or well.
+type TypeReport struct {
+	Type             models.PokemonType `json:"type"`
+	WeakMembers      []int              `json:"weak_members"`      // Multiplier above 1
+	ResistantMembers []int              `json:"resistant_members"` // Multiplier below 1, immunities included
+}
+
+// TeamAnalysis summarizes a party's defensive and offensive typing.
+type TeamAnalysis struct {
+	// SharedWeaknesses are attacking types that hit at least two members
+	// super effectively and are resisted by fewer members than they hurt.
+	SharedWeaknesses []TypeReport `json:"shared_weaknesses"`
+	// Resistances are attacking types at least one member resists.
+	Resistances []TypeReport `json:"resistances"`
+	// UncoveredTypes are defending types none of the members' own types
+	// (their STAB) hit super effectively.
+	UncoveredTypes []models.PokemonType `json:"uncovered_types"`
+}
+
+// AnalyzeTeam computes the coverage report for a party.
+func AnalyzeTeam(members []TeamMember) TeamAnalysis {
+	analysis := TeamAnalysis{
+		SharedWeaknesses: []TypeReport{},
+		Resistances:      []TypeReport{},
+		UncoveredTypes:   []models.PokemonType{},
+	}
+
+	// 1. Defense: how each attacking type fares against the party
+	for _, attack := range models.AllPokemonTypes {
+		report := TypeReport{Type: attack, WeakMembers: []int{}, ResistantMembers: []int{}}
+		for _, member := range members {
+			m := Effectiveness(attack, member.Types)
+			switch {
+			case m > 1:
+				report.WeakMembers = append(report.WeakMembers, member.ID)
+			case m < 1:
+				report.ResistantMembers = append(report.ResistantMembers, member.ID)
+			}
+		}
+
+		if len(report.WeakMembers) >= 2 && len(report.WeakMembers) > len(report.ResistantMembers) {
+			analysis.SharedWeaknesses = append(analysis.SharedWeaknesses, report)
+		}
+		if len(report.ResistantMembers) > 0 {
+			analysis.Resistances = append(analysis.Resistances, report)
+		}
+	}
+
+	// 2. Offense: which defending types no member's STAB can hit hard
+	for _, defend := range models.AllPokemonTypes {
+		covered := false
+		for _, member := range members {
+			for _, attack := range member.Types.List() {
+				if Multiplier(attack, defend) > 1 {
+					covered = true
+				}
+			}
+		}
+		if !covered {
+			analysis.UncoveredTypes = append(analysis.UncoveredTypes, defend)
+		}
+	}
+
+	// Most dangerous weaknesses and broadest resistances first
+	sort.SliceStable(analysis.SharedWeaknesses, func(i, j int) bool {
+		return len(analysis.SharedWeaknesses[i].WeakMembers) > len(analysis.SharedWeaknesses[j].WeakMembers)
+	})
+	sort.SliceStable(analysis.Resistances, func(i, j int) bool {
+		return len(analysis.Resistances[i].ResistantMembers) > len(analysis.Resistances[j].ResistantMembers)
+	})
+
+	return analysis
+}
@@ -0,0 +1,149 @@
+// Package typechart computes type effectiveness using the Generation VI+ type chart.
+package typechart
+
+import (
+	"sort"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+)
+
+// chart holds every non-neutral matchup: chart[attack][defend] = multiplier.
+// Pairs that are missing deal normal (1x) damage.
+var chart = map[models.PokemonType]map[models.PokemonType]float64{
+	models.TypeNormal: {
+		models.TypeRock: 0.5, models.TypeGhost: 0, models.TypeSteel: 0.5,
+	},
+	models.TypeFire: {
+		models.TypeFire: 0.5, models.TypeWater: 0.5, models.TypeGrass: 2, models.TypeIce: 2,
+		models.TypeBug: 2, models.TypeRock: 0.5, models.TypeDragon: 0.5, models.TypeSteel: 2,
+	},
+	models.TypeWater: {
+		models.TypeFire: 2, models.TypeWater: 0.5, models.TypeGrass: 0.5, models.TypeGround: 2,
+		models.TypeRock: 2, models.TypeDragon: 0.5,
+	},
+	models.TypeElectric: {
+		models.TypeWater: 2, models.TypeElectric: 0.5, models.TypeGrass: 0.5, models.TypeGround: 0,
+		models.TypeFlying: 2, models.TypeDragon: 0.5,
+	},
+	models.TypeGrass: {
+		models.TypeFire: 0.5, models.TypeWater: 2, models.TypeGrass: 0.5, models.TypePoison: 0.5,
+		models.TypeGround: 2, models.TypeFlying: 0.5, models.TypeBug: 0.5, models.TypeRock: 2,
+		models.TypeDragon: 0.5, models.TypeSteel: 0.5,
+	},
+	models.TypeIce: {
+		models.TypeFire: 0.5, models.TypeWater: 0.5, models.TypeGrass: 2, models.TypeIce: 0.5,
+		models.TypeGround: 2, models.TypeFlying: 2, models.TypeDragon: 2, models.TypeSteel: 0.5,
+	},
+	models.TypeFighting: {
+		models.TypeNormal: 2, models.TypeIce: 2, models.TypePoison: 0.5, models.TypeFlying: 0.5,
+		models.TypePsychic: 0.5, models.TypeBug: 0.5, models.TypeRock: 2, models.TypeGhost: 0,
+		models.TypeDark: 2, models.TypeSteel: 2, models.TypeFairy: 0.5,
+	},
+	models.TypePoison: {
+		models.TypeGrass: 2, models.TypePoison: 0.5, models.TypeGround: 0.5, models.TypeRock: 0.5,
+		models.TypeGhost: 0.5, models.TypeSteel: 0, models.TypeFairy: 2,
+	},
+	models.TypeGround: {
+		models.TypeFire: 2, models.TypeElectric: 2, models.TypeGrass: 0.5, models.TypePoison: 2,
+		models.TypeFlying: 0, models.TypeBug: 0.5, models.TypeRock: 2, models.TypeSteel: 2,
+	},
+	models.TypeFlying: {
+		models.TypeElectric: 0.5, models.TypeGrass: 2, models.TypeFighting: 2, models.TypeBug: 2,
+		models.TypeRock: 0.5, models.TypeSteel: 0.5,
+	},
+	models.TypePsychic: {
+		models.TypeFighting: 2, models.TypePoison: 2, models.TypePsychic: 0.5, models.TypeDark: 0,
+		models.TypeSteel: 0.5,
+	},
+	models.TypeBug: {
+		models.TypeFire: 0.5, models.TypeGrass: 2, models.TypeFighting: 0.5, models.TypePoison: 0.5,
+		models.TypeFlying: 0.5, models.TypePsychic: 2, models.TypeGhost: 0.5, models.TypeDark: 2,
+		models.TypeSteel: 0.5, models.TypeFairy: 0.5,
+	},
+	models.TypeRock: {
+		models.TypeFire: 2, models.TypeIce: 2, models.TypeFighting: 0.5, models.TypeGround: 0.5,
+		models.TypeFlying: 2, models.TypeBug: 2, models.TypeSteel: 0.5,
+	},
+	models.TypeGhost: {
+		models.TypeNormal: 0, models.TypePsychic: 2, models.TypeGhost: 2, models.TypeDark: 0.5,
+	},
+	models.TypeDragon: {
+		models.TypeDragon: 2, models.TypeSteel: 0.5, models.TypeFairy: 0,
+	},
+	models.TypeDark: {
+		models.TypeFighting: 0.5, models.TypePsychic: 2, models.TypeGhost: 2, models.TypeDark: 0.5,
+		models.TypeFairy: 0.5,
+	},
+	models.TypeSteel: {
+		models.TypeFire: 0.5, models.TypeWater: 0.5, models.TypeElectric: 0.5, models.TypeIce: 2,
+		models.TypeRock: 2, models.TypeSteel: 0.5, models.TypeFairy: 2,
+	},
+	models.TypeFairy: {
+		models.TypeFire: 0.5, models.TypeFighting: 2, models.TypePoison: 0.5, models.TypeDragon: 2,
+		models.TypeDark: 2, models.TypeSteel: 0.5,
+	},
+}
+
+// Multiplier returns the damage multiplier of one attacking type against one defending type.
+func Multiplier(attack, defend models.PokemonType) float64 {
+	if m, ok := chart[attack][defend]; ok {
+		return m
+	}
+	return 1
+}
+
+// Effectiveness returns the combined multiplier against a single or dual-typed defender,
+// e.g. 4 for ice against grass/flying, 0 for ground against anything flying.
+func Effectiveness(attack models.PokemonType, defend models.Types) float64 {
+	m := Multiplier(attack, defend.Primary)
+	if defend.Secondary != "" {
+		m *= Multiplier(attack, defend.Secondary)
+	}
+	return m
+}
+
+// Matchup is the multiplier of one attacking type.
+type Matchup struct {
+	Type       models.PokemonType `json:"type"`
+	Multiplier float64            `json:"multiplier"`
+}
+
+// DefenseProfile groups every attacking type by how well a defender takes it.
+type DefenseProfile struct {
+	Types       models.Types         `json:"types"`
+	Weaknesses  []Matchup            `json:"weaknesses"`  // Multiplier above 1
+	Resistances []Matchup            `json:"resistances"` // Multiplier between 0 and 1
+	Immunities  []models.PokemonType `json:"immunities"`  // Multiplier of 0
+}
+
+// Defense builds the profile of a defender, strongest weaknesses and resistances first.
+func Defense(defend models.Types) DefenseProfile {
+	profile := DefenseProfile{
+		Types:       defend,
+		Weaknesses:  []Matchup{},
+		Resistances: []Matchup{},
+		Immunities:  []models.PokemonType{},
+	}
+
+	for _, attack := range models.AllPokemonTypes {
+		m := Effectiveness(attack, defend)
+		switch {
+		case m == 0:
+			profile.Immunities = append(profile.Immunities, attack)
+		case m > 1:
+			profile.Weaknesses = append(profile.Weaknesses, Matchup{Type: attack, Multiplier: m})
+		case m < 1:
+			profile.Resistances = append(profile.Resistances, Matchup{Type: attack, Multiplier: m})
+		}
+	}
+
+	// Stable sorts keep canonical type order within the same multiplier
+	sort.SliceStable(profile.Weaknesses, func(i, j int) bool {
+		return profile.Weaknesses[i].Multiplier > profile.Weaknesses[j].Multiplier
+	})
+	sort.SliceStable(profile.Resistances, func(i, j int) bool {
+		return profile.Resistances[i].Multiplier < profile.Resistances[j].Multiplier
+	})
+
+	return profile
+}
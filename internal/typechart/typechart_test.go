@@ -0,0 +1,253 @@
+package typechart
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sanskarchoudhry/pokedex-backend/internal/models"
+)
+
+func TestMultiplier(t *testing.T) {
+	tests := []struct {
+		name   string
+		attack models.PokemonType
+		defend models.PokemonType
+		want   float64
+	}{
+		{"super effective", models.TypeFire, models.TypeGrass, 2},
+		{"not very effective", models.TypeWater, models.TypeDragon, 0.5},
+		{"ground can't hit flying", models.TypeGround, models.TypeFlying, 0},
+		{"normal can't hit ghost", models.TypeNormal, models.TypeGhost, 0},
+		{"neutral", models.TypeNormal, models.TypeWater, 1},
+		{"unknown type is neutral", "shadow", models.TypeFire, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Multiplier(tt.attack, tt.defend); got != tt.want {
+				t.Errorf("Multiplier(%s, %s) = %v, want %v", tt.attack, tt.defend, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEffectiveness(t *testing.T) {
+	tests := []struct {
+		name   string
+		attack models.PokemonType
+		defend models.Types
+		want   float64
+	}{
+		{"double weakness", models.TypeIce, models.Types{Primary: models.TypeGrass, Secondary: models.TypeFlying}, 4},
+		{"weakness and neutral", models.TypeFire, models.Types{Primary: models.TypeGrass, Secondary: models.TypePoison}, 2},
+		{"double resistance", models.TypeWater, models.Types{Primary: models.TypeWater, Secondary: models.TypeDragon}, 0.25},
+		{"weakness cancelled by resistance", models.TypeFighting, models.Types{Primary: models.TypeNormal, Secondary: models.TypeFlying}, 1},
+		{"immunity beats weakness", models.TypeGround, models.Types{Primary: models.TypeElectric, Secondary: models.TypeFlying}, 0},
+		{"single type", models.TypeElectric, models.Types{Primary: models.TypeWater}, 2},
+		{"neutral", models.TypeNormal, models.Types{Primary: models.TypeFire, Secondary: models.TypeWater}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Effectiveness(tt.attack, tt.defend); got != tt.want {
+				t.Errorf("Effectiveness(%s, %v) = %v, want %v", tt.attack, tt.defend, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChartIsComplete(t *testing.T) {
+	for attack := range chart {
+		if !isKnownType(attack) {
+			t.Errorf("chart has unknown attacking type %q", attack)
+		}
+		for defend, m := range chart[attack] {
+			if !isKnownType(defend) {
+				t.Errorf("chart has unknown defending type %q under %s", defend, attack)
+			}
+			if m != 0 && m != 0.5 && m != 2 {
+				t.Errorf("chart[%s][%s] = %v, want 0, 0.5 or 2", attack, defend, m)
+			}
+		}
+	}
+	if len(chart) != len(models.AllPokemonTypes) {
+		t.Errorf("chart has %d attacking types, want %d", len(chart), len(models.AllPokemonTypes))
+	}
+}
+
+func isKnownType(t models.PokemonType) bool {
+	for _, known := range models.AllPokemonTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDefense(t *testing.T) {
+	tests := []struct {
+		name            string
+		defend          models.Types
+		wantWeaknesses  []Matchup
+		wantResistances []Matchup
+		wantImmunities  []models.PokemonType
+	}{
+		{
+			name:   "fire/flying sorts by multiplier, then type order",
+			defend: models.Types{Primary: models.TypeFire, Secondary: models.TypeFlying},
+			wantWeaknesses: []Matchup{
+				{models.TypeRock, 4}, {models.TypeWater, 2}, {models.TypeElectric, 2},
+			},
+			wantResistances: []Matchup{
+				{models.TypeGrass, 0.25}, {models.TypeBug, 0.25},
+				{models.TypeFire, 0.5}, {models.TypeFighting, 0.5}, {models.TypeSteel, 0.5}, {models.TypeFairy, 0.5},
+			},
+			wantImmunities: []models.PokemonType{models.TypeGround},
+		},
+		{
+			name:   "ghost has two immunities",
+			defend: models.Types{Primary: models.TypeGhost},
+			wantWeaknesses: []Matchup{
+				{models.TypeGhost, 2}, {models.TypeDark, 2},
+			},
+			wantResistances: []Matchup{
+				{models.TypePoison, 0.5}, {models.TypeBug, 0.5},
+			},
+			wantImmunities: []models.PokemonType{models.TypeNormal, models.TypeFighting},
+		},
+		{
+			name:            "normal has empty lists, not nil",
+			defend:          models.Types{Primary: models.TypeNormal},
+			wantWeaknesses:  []Matchup{{models.TypeFighting, 2}},
+			wantResistances: []Matchup{},
+			wantImmunities:  []models.PokemonType{models.TypeGhost},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Defense(tt.defend)
+			if got.Types != tt.defend {
+				t.Errorf("Types = %v, want %v", got.Types, tt.defend)
+			}
+			if !reflect.DeepEqual(got.Weaknesses, tt.wantWeaknesses) {
+				t.Errorf("Weaknesses = %v, want %v", got.Weaknesses, tt.wantWeaknesses)
+			}
+			if !reflect.DeepEqual(got.Resistances, tt.wantResistances) {
+				t.Errorf("Resistances = %v, want %v", got.Resistances, tt.wantResistances)
+			}
+			if !reflect.DeepEqual(got.Immunities, tt.wantImmunities) {
+				t.Errorf("Immunities = %v, want %v", got.Immunities, tt.wantImmunities)
+			}
+		})
+	}
+}
+
+func TestAnalyzeTeam(t *testing.T) {
+	grassPoison := TeamMember{ID: 1, Types: models.Types{Primary: models.TypeGrass, Secondary: models.TypePoison}}
+	grass := TeamMember{ID: 2, Types: models.Types{Primary: models.TypeGrass}}
+	water := TeamMember{ID: 3, Types: models.Types{Primary: models.TypeWater}}
+
+	tests := []struct {
+		name         string
+		members      []TeamMember
+		wantShared   []TypeReport
+		wantUncover  []models.PokemonType
+		wantResisted int // Number of attacking types at least one member resists
+	}{
+		{
+			name:    "two grass types share weaknesses",
+			members: []TeamMember{grassPoison, grass},
+			wantShared: []TypeReport{
+				{Type: models.TypeFire, WeakMembers: []int{1, 2}, ResistantMembers: []int{}},
+				{Type: models.TypeIce, WeakMembers: []int{1, 2}, ResistantMembers: []int{}},
+				{Type: models.TypeFlying, WeakMembers: []int{1, 2}, ResistantMembers: []int{}},
+			},
+			// Grass and poison STAB only hit water, ground, rock, grass and fairy hard
+			wantUncover: []models.PokemonType{
+				models.TypeNormal, models.TypeFire, models.TypeElectric, models.TypeIce,
+				models.TypeFighting, models.TypePoison, models.TypeFlying, models.TypePsychic,
+				models.TypeBug, models.TypeGhost, models.TypeDragon, models.TypeDark, models.TypeSteel,
+			},
+			wantResisted: 6, // water, electric, grass, fighting, ground, fairy
+		},
+		{
+			name:    "one resisting member doesn't hide a weakness of two",
+			members: []TeamMember{grassPoison, grass, water},
+			wantShared: []TypeReport{
+				{Type: models.TypeFire, WeakMembers: []int{1, 2}, ResistantMembers: []int{3}},
+				{Type: models.TypeIce, WeakMembers: []int{1, 2}, ResistantMembers: []int{3}},
+				{Type: models.TypeFlying, WeakMembers: []int{1, 2}, ResistantMembers: []int{}},
+			},
+			wantUncover: []models.PokemonType{
+				models.TypeNormal, models.TypeElectric, models.TypeIce,
+				models.TypeFighting, models.TypePoison, models.TypeFlying, models.TypePsychic,
+				models.TypeBug, models.TypeGhost, models.TypeDragon, models.TypeDark, models.TypeSteel,
+			},
+			wantResisted: 9, // plus fire, ice and steel from water
+		},
+		{
+			name: "as many resisting members as weak ones cancel out",
+			members: []TeamMember{
+				{ID: 1, Types: models.Types{Primary: models.TypeGrass}},
+				{ID: 2, Types: models.Types{Primary: models.TypeBug}},
+				{ID: 3, Types: models.Types{Primary: models.TypeWater}},
+				{ID: 4, Types: models.Types{Primary: models.TypeFire}},
+			},
+			// Fire hurts grass and bug, but water and fire resist it
+			wantShared: []TypeReport{
+				{Type: models.TypeFlying, WeakMembers: []int{1, 2}, ResistantMembers: []int{}},
+				{Type: models.TypeRock, WeakMembers: []int{2, 4}, ResistantMembers: []int{}},
+			},
+			wantUncover: []models.PokemonType{
+				models.TypeNormal, models.TypeElectric, models.TypeFighting, models.TypePoison,
+				models.TypeFlying, models.TypeGhost, models.TypeDragon, models.TypeFairy,
+			},
+			wantResisted: 10,
+		},
+		{
+			name:         "empty team covers nothing",
+			members:      nil,
+			wantShared:   []TypeReport{},
+			wantUncover:  models.AllPokemonTypes,
+			wantResisted: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := AnalyzeTeam(tt.members)
+			if !reflect.DeepEqual(got.SharedWeaknesses, tt.wantShared) {
+				t.Errorf("SharedWeaknesses = %+v, want %+v", got.SharedWeaknesses, tt.wantShared)
+			}
+			if !reflect.DeepEqual(got.UncoveredTypes, tt.wantUncover) {
+				t.Errorf("UncoveredTypes = %v, want %v", got.UncoveredTypes, tt.wantUncover)
+			}
+			if len(got.Resistances) != tt.wantResisted {
+				t.Errorf("got %d resisted types, want %d", len(got.Resistances), tt.wantResisted)
+			}
+		})
+	}
+}
+
+func TestAnalyzeTeamSortsByMembersHurt(t *testing.T) {
+	members := []TeamMember{
+		{ID: 1, Types: models.Types{Primary: models.TypeGrass}},
+		{ID: 2, Types: models.Types{Primary: models.TypeBug}},
+		{ID: 3, Types: models.Types{Primary: models.TypeIce}},
+	}
+
+	got := AnalyzeTeam(members).SharedWeaknesses
+	if len(got) == 0 {
+		t.Fatal("expected shared weaknesses")
+	}
+	// Fire hurts all three; it must come before anything that hurts two
+	if got[0].Type != models.TypeFire || len(got[0].WeakMembers) != 3 {
+		t.Errorf("first shared weakness = %+v, want fire hitting 3 members", got[0])
+	}
+	for i := 1; i < len(got); i++ {
+		if len(got[i].WeakMembers) > len(got[i-1].WeakMembers) {
+			t.Errorf("shared weaknesses not sorted: %+v before %+v", got[i-1], got[i])
+		}
+	}
+}
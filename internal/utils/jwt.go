@@ -0,0 +1,267 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	accessTokenTTL       = 15 * time.Minute
+	verificationTokenTTL = 24 * time.Hour
+	mfaChallengeTTL      = 5 * time.Minute
+)
+
+// Token types, carried in the "typ" claim so a token issued for one
+// purpose can't be presented for another.
+const (
+	TokenTypeAccess      = "access"
+	TokenTypeVerifyEmail = "verify_email"
+	// TokenTypeMFAChallenge proves the password was right; a TOTP code turns it into a session
+	TokenTypeMFAChallenge = "mfa_challenge"
+)
+
+// SigningKey is one entry of the key set. Keys without a private half can only verify.
+type SigningKey struct {
+	ID         string
+	Method     jwt.SigningMethod
+	PrivateKey any // *rsa.PrivateKey, ed25519.PrivateKey or []byte (HMAC)
+	PublicKey  any // *rsa.PublicKey, ed25519.PublicKey or []byte (HMAC)
+}
+
+// CanSign reports whether the key holds private material.
+func (k SigningKey) CanSign() bool {
+	return k.PrivateKey != nil
+}
+
+// NewHMACKey builds an HS256 key from a shared secret.
+func NewHMACKey(id string, secret []byte) SigningKey {
+	return SigningKey{ID: id, Method: jwt.SigningMethodHS256, PrivateKey: secret, PublicKey: secret}
+}
+
+// LoadSigningKeys reads PEM encoded RSA (RS256) or Ed25519 (EdDSA) keys from disk.
+// The kid of each key is its file name without the extension.
+func LoadSigningKeys(paths []string) ([]SigningKey, error) {
+	keys := make([]SigningKey, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading key file %s: %w", path, err)
+		}
+
+		kid := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		key, err := parsePEMKey(kid, data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing key file %s: %w", path, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func parsePEMKey(kid string, data []byte) (SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return SigningKey{}, errors.New("no PEM block found")
+	}
+
+	var (
+		parsed any
+		err    error
+	)
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return SigningKey{}, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return SigningKey{}, err
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		return SigningKey{ID: kid, Method: jwt.SigningMethodRS256, PrivateKey: k, PublicKey: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		return SigningKey{ID: kid, Method: jwt.SigningMethodRS256, PublicKey: k}, nil
+	case ed25519.PrivateKey:
+		return SigningKey{ID: kid, Method: jwt.SigningMethodEdDSA, PrivateKey: k, PublicKey: k.Public()}, nil
+	case ed25519.PublicKey:
+		return SigningKey{ID: kid, Method: jwt.SigningMethodEdDSA, PublicKey: k}, nil
+	default:
+		return SigningKey{}, fmt.Errorf("unsupported key type %T", parsed)
+	}
+}
+
+// JWTManager signs access tokens with the active key and verifies them against
+// every configured key, so keys can be rotated without logging everyone out.
+type JWTManager struct {
+	keys   map[string]SigningKey
+	active SigningKey
+}
+
+func NewJWTManager(keys []SigningKey, activeKeyID string) (*JWTManager, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one signing key is required")
+	}
+
+	m := &JWTManager{keys: make(map[string]SigningKey, len(keys))}
+	for _, key := range keys {
+		if _, dup := m.keys[key.ID]; dup {
+			return nil, fmt.Errorf("duplicate key id %q", key.ID)
+		}
+		m.keys[key.ID] = key
+	}
+
+	// Default to the first key when no kid is configured
+	if activeKeyID == "" {
+		activeKeyID = keys[0].ID
+	}
+	active, ok := m.keys[activeKeyID]
+	if !ok {
+		return nil, fmt.Errorf("active key %q not found", activeKeyID)
+	}
+	if !active.CanSign() {
+		return nil, fmt.Errorf("active key %q has no private key", activeKeyID)
+	}
+	m.active = active
+
+	return m, nil
+}
+
+// GenerateAccessToken issues a short-lived JWT for the user.
+func (m *JWTManager) GenerateAccessToken(userID int, email string, emailVerified bool, role string) (string, error) {
+	return m.sign(jwt.MapClaims{
+		"typ":            TokenTypeAccess,
+		"sub":            userID,
+		"email":          email,
+		"email_verified": emailVerified,
+		"role":           role,
+		"exp":            time.Now().Add(accessTokenTTL).Unix(), // Expires in 15 mins
+		"iat":            time.Now().Unix(),
+	})
+}
+
+// GenerateVerificationToken issues the token emailed to confirm an address.
+// It names the address, so it stops working if the account's email changes.
+func (m *JWTManager) GenerateVerificationToken(userID int, email string) (string, error) {
+	return m.sign(jwt.MapClaims{
+		"typ":   TokenTypeVerifyEmail,
+		"sub":   userID,
+		"email": email,
+		"exp":   time.Now().Add(verificationTokenTTL).Unix(),
+		"iat":   time.Now().Unix(),
+	})
+}
+
+// GenerateMFAChallengeToken issues the short-lived token handed out between the
+// password and the second factor.
+func (m *JWTManager) GenerateMFAChallengeToken(userID int) (string, error) {
+	return m.sign(jwt.MapClaims{
+		"typ": TokenTypeMFAChallenge,
+		"sub": userID,
+		"exp": time.Now().Add(mfaChallengeTTL).Unix(),
+		"iat": time.Now().Unix(),
+	})
+}
+
+func (m *JWTManager) sign(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(m.active.Method, claims)
+	token.Header["kid"] = m.active.ID
+	return token.SignedString(m.active.PrivateKey)
+}
+
+// ValidateToken checks the signature against the key named in the "kid" header
+// and that the token was issued as tokenType.
+func (m *JWTManager) ValidateToken(tokenString, tokenType string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := m.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+		// Never let the token pick a different algorithm than the key was made for
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return key.PublicKey, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
+		if typ, _ := claims["typ"].(string); typ != tokenType {
+			return nil, fmt.Errorf("token type %q, expected %q", typ, tokenType)
+		}
+		return claims, nil
+	}
+
+	return nil, fmt.Errorf("invalid token")
+}
+
+// JWK is a single public key in JSON Web Key format (RFC 7517).
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS is the document served at /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns every asymmetric public key. HMAC secrets are never published.
+func (m *JWTManager) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for _, key := range m.keys {
+		switch pub := key.PublicKey.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "RSA",
+				Kid: key.ID,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				Kty: "OKP",
+				Kid: key.ID,
+				Use: "sig",
+				Alg: key.Method.Alg(),
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(pub),
+			})
+		}
+	}
+	// Map iteration is random; keep the document stable for caches
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
@@ -5,30 +5,16 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
-	"fmt"
-	"time"
-
-	"github.com/golang-jwt/jwt/v5"
 )
 
-// Define your secret key (In production, load this from ENV variables!)
-var jwtSecret = []byte("super-secret-key-change-me")
-
-// 1. Generate Access Token (JWT)
-func GenerateAccessToken(userID int, email string) (string, error) {
-	claims := jwt.MapClaims{
-		"sub":   userID,
-		"email": email,
-		"exp":   time.Now().Add(15 * time.Minute).Unix(), // Expires in 15 mins
-		"iat":   time.Now().Unix(),
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+// 1. Generate Refresh Token (Random String)
+func GenerateRefreshToken() (string, string, error) {
+	return GenerateSecureToken()
 }
 
-// 2. Generate Refresh Token (Random String)
-func GenerateRefreshToken() (string, string, error) {
+// GenerateSecureToken returns a random token and the hash to store in its place.
+// Password reset links use the same format as refresh tokens.
+func GenerateSecureToken() (string, string, error) {
 	// Generate 32 bytes of random data
 	bytes := make([]byte, 32)
 	if _, err := rand.Read(bytes); err != nil {
@@ -45,24 +31,13 @@ func GenerateRefreshToken() (string, string, error) {
 	return rawToken, hashedToken, nil
 }
 
-// 3. Validate Access Token
-func ValidateToken(tokenString string) (jwt.MapClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return jwtSecret, nil
-	})
-
-	if err != nil {
-		return nil, err
-	}
-
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		return claims, nil
+// 2. Generate Token Family ID (groups rotated refresh tokens from one login)
+func GenerateTokenFamilyID() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
 	}
-
-	return nil, fmt.Errorf("invalid token")
+	return hex.EncodeToString(bytes), nil
 }
 
 func HashToken(rawToken string) string {
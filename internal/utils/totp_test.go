@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 seed of RFC 6238 Appendix B, "12345678901234567890", in base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// TestValidateTOTPRFCVectors checks the SHA-1 vectors of RFC 6238 Appendix B. The
+// RFC lists 8 digit codes; 6 digit codes are their last six digits.
+func TestValidateTOTPRFCVectors(t *testing.T) {
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},          // 94287082
+		{1111111109, "081804"},  // 07081804
+		{1111111111, "050471"},  // 14050471
+		{1234567890, "005924"},  // 89005924
+		{2000000000, "279037"},  // 69279037
+		{20000000000, "353130"}, // 65353130
+	}
+
+	for _, tt := range tests {
+		at := time.Unix(tt.unix, 0)
+		step, ok := ValidateTOTP(rfcSecret, tt.code, at)
+		if !ok {
+			t.Errorf("ValidateTOTP(%q) at %d rejected, want accepted", tt.code, tt.unix)
+			continue
+		}
+		if want := tt.unix / 30; step != want {
+			t.Errorf("ValidateTOTP(%q) at %d step = %d, want %d", tt.code, tt.unix, step, want)
+		}
+	}
+}
+
+func TestValidateTOTPSkew(t *testing.T) {
+	// 1111111109 is the last second of step 37037036; the code belongs to that step
+	const code, step = "081804", int64(37037036)
+	stepStart := time.Unix(step*30, 0)
+
+	tests := []struct {
+		name string
+		at   time.Time
+		ok   bool
+	}{
+		{"start of its step", stepStart, true},
+		{"end of its step", stepStart.Add(29 * time.Second), true},
+		{"one step early", stepStart.Add(-30 * time.Second), true},
+		{"one step late", stepStart.Add(59 * time.Second), true},
+		{"just over one step early", stepStart.Add(-31 * time.Second), false},
+		{"two steps late", stepStart.Add(60 * time.Second), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ValidateTOTP(rfcSecret, code, tt.at)
+			if ok != tt.ok {
+				t.Fatalf("ValidateTOTP() ok = %v, want %v", ok, tt.ok)
+			}
+			// The matched step is the code's own, not the current one, so replays are caught
+			if ok && got != step {
+				t.Errorf("ValidateTOTP() step = %d, want %d", got, step)
+			}
+		})
+	}
+}
+
+func TestValidateTOTPRejectsMalformedInput(t *testing.T) {
+	at := time.Unix(59, 0)
+	tests := []struct {
+		name, secret, code string
+	}{
+		{"eight digits", rfcSecret, "94287082"},
+		{"five digits", rfcSecret, "28708"},
+		{"empty code", rfcSecret, ""},
+		{"invalid secret", "not base32!", "287082"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := ValidateTOTP(tt.secret, tt.code, at); ok {
+				t.Errorf("ValidateTOTP(%q, %q) accepted, want rejected", tt.secret, tt.code)
+			}
+		})
+	}
+}
+
+func TestValidateTOTPLowercaseSecret(t *testing.T) {
+	if _, ok := ValidateTOTP("gezdgnbvgy3tqojqgezdgnbvgy3tqojq", "287082", time.Unix(59, 0)); !ok {
+		t.Error("ValidateTOTP() rejected a lowercase secret")
+	}
+}